@@ -15,6 +15,8 @@
 package ini
 
 import (
+	"bytes"
+	"strconv"
 	"testing"
 )
 
@@ -114,3 +116,76 @@ func Benchmark_Key_SetValue_VisSection(b *testing.B) {
 		sec.Key("NAME").SetValue("10")
 	}
 }
+
+// newLargeData returns content of given number of keys in sections of given size.
+func newLargeData(keys, keysPerSection int) []byte {
+	var buf bytes.Buffer
+	for i := 0; i < keys; i++ {
+		if i%keysPerSection == 0 {
+			buf.WriteString("\n[section" + strconv.Itoa(i/keysPerSection) + "]\n")
+		}
+		buf.WriteString("; comment of key " + strconv.Itoa(i) + "\n")
+		buf.WriteString("key" + strconv.Itoa(i) + " = value of key " + strconv.Itoa(i) + " # inline\n")
+	}
+	return buf.Bytes()
+}
+
+func benchmarkLoad(b *testing.B, data []byte) {
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Load(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_Load_100kKeys_OneSection(b *testing.B) {
+	benchmarkLoad(b, newLargeData(100000, 100000))
+}
+
+func Benchmark_Load_100kKeys_ManySections(b *testing.B) {
+	benchmarkLoad(b, newLargeData(100000, 10))
+}
+
+func Benchmark_Load_100kKeys_Quoted(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("[section]\n")
+	for i := 0; i < 100000; i++ {
+		buf.WriteString("key" + strconv.Itoa(i) + " = `value # of ; key " + strconv.Itoa(i) + "`\n")
+	}
+	benchmarkLoad(b, buf.Bytes())
+}
+
+func Benchmark_Load_10kContinuationLines(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("[section]\nkey = \"\"\"")
+	for i := 0; i < 10000; i++ {
+		buf.WriteString("line " + strconv.Itoa(i) + "\n")
+	}
+	buf.WriteString("\"\"\"\nother = ")
+	for i := 0; i < 10000; i++ {
+		buf.WriteString("line " + strconv.Itoa(i) + " \\\n")
+	}
+	buf.WriteString("end\n")
+	benchmarkLoad(b, buf.Bytes())
+}
+
+func Benchmark_Snapshot_Value_Parallel(b *testing.B) {
+	snap := newTestFile(true).Snapshot()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			snap.Section("").Value("NAME")
+		}
+	})
+}
+
+func Benchmark_Key_String_Parallel(b *testing.B) {
+	c := newTestFile(true)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = c.Section("").Key("NAME").String()
+		}
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BooleanStyle is the pair of strings written for boolean values.
+type BooleanStyle struct {
+	True  string
+	False string
+}
+
+// Common styles of boolean values.
+var (
+	BooleanStyleTrueFalse = BooleanStyle{True: "true", False: "false"}
+	BooleanStyleYesNo     = BooleanStyle{True: "yes", False: "no"}
+	BooleanStyleOnOff     = BooleanStyle{True: "on", False: "off"}
+	BooleanStyleOneZero   = BooleanStyle{True: "1", False: "0"}
+)
+
+// SystemdBooleanStates returns the boolean states accepted by systemd unit files.
+// Docs: https://www.freedesktop.org/software/systemd/man/systemd.syntax.html
+func SystemdBooleanStates() map[string]bool {
+	return map[string]bool{
+		"1": true, "yes": true, "y": true, "true": true, "t": true, "on": true,
+		"0": false, "no": false, "n": false, "false": false, "f": false, "off": false,
+	}
+}
+
+// MySQLBooleanStates returns the boolean states accepted by MySQL option files (my.cnf).
+// Docs: https://dev.mysql.com/doc/refman/8.0/en/option-modifiers.html
+func MySQLBooleanStates() map[string]bool {
+	return map[string]bool{
+		"1": true, "true": true, "on": true,
+		"0": false, "false": false, "off": false,
+	}
+}
+
+// PHPBooleanStates returns the boolean states accepted by php.ini, where an empty value is false.
+// Docs: https://www.php.net/manual/en/function.parse-ini-file.php
+func PHPBooleanStates() map[string]bool {
+	return map[string]bool{
+		"1": true, "yes": true, "true": true, "on": true,
+		"0": false, "no": false, "false": false, "off": false, "none": false, "": false,
+	}
+}
+
+// parseBool returns the boolean value represented by the string
+// using boolean states of the file when set.
+func (f *File) parseBool(str string) (bool, error) {
+	if f.options.BooleanStates == nil {
+		return parseBool(str)
+	}
+
+	state := str
+	if !f.options.StrictBooleanStates {
+		state = strings.ToLower(str)
+	}
+	val, ok := f.options.BooleanStates[state]
+	if !ok {
+		return false, invalidBoolError(str)
+	}
+	return val, nil
+}
+
+// formatBool returns the string written for the boolean value
+// using boolean style of the file when set.
+func (f *File) formatBool(b bool) string {
+	style := f.options.BooleanStyle
+	if style == (BooleanStyle{}) {
+		return strconv.FormatBool(b)
+	}
+	if b {
+		return style.True
+	}
+	return style.False
+}
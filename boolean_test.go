@@ -0,0 +1,160 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBooleanStates(t *testing.T) {
+	t.Run("use a preset vocabulary", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{BooleanStates: MySQLBooleanStates()}, []byte(`
+skip_networking = ON
+log_bin = 0
+flags = on, OFF, True
+bad = yes
+`))
+		require.NoError(t, err)
+		sec := f.Section("")
+
+		v, err := sec.Key("skip_networking").Bool()
+		require.NoError(t, err)
+		assert.True(t, v)
+		assert.False(t, sec.Key("log_bin").MustBool(true))
+		assert.Equal(t, []bool{true, false, true}, sec.Key("flags").Bools(","))
+
+		_, err = sec.Key("bad").Bool()
+		assert.Error(t, err)
+	})
+
+	t.Run("empty value with PHP vocabulary", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{BooleanStates: PHPBooleanStates()}, []byte(`
+display_errors = On
+log_errors =
+html_errors = None
+`))
+		require.NoError(t, err)
+		sec := f.Section("")
+
+		assert.True(t, sec.Key("display_errors").MustBool())
+		v, err := sec.Key("log_errors").Bool()
+		require.NoError(t, err)
+		assert.False(t, v)
+		assert.False(t, sec.Key("html_errors").MustBool(true))
+	})
+
+	t.Run("strict matching", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{
+			BooleanStates:       map[string]bool{"On": true, "Off": false},
+			StrictBooleanStates: true,
+		}, []byte(`
+a = On
+b = ON
+`))
+		require.NoError(t, err)
+		sec := f.Section("")
+
+		assert.True(t, sec.Key("a").MustBool())
+		_, err = sec.Key("b").Bool()
+		assert.Error(t, err)
+		_, err = sec.Key("b").StrictBools(",")
+		assert.Error(t, err)
+	})
+
+	t.Run("map to struct", func(t *testing.T) {
+		type unit struct {
+			Enabled  bool   `ini:"Enabled"`
+			Disabled *bool  `ini:"Disabled"`
+			Flags    []bool `ini:"Flags"`
+		}
+
+		f, err := LoadSources(LoadOptions{BooleanStates: SystemdBooleanStates()}, []byte(`
+Enabled = y
+Disabled = OFF
+Flags = t, n
+`))
+		require.NoError(t, err)
+
+		u := new(unit)
+		require.NoError(t, f.StrictMapTo(u))
+		assert.True(t, u.Enabled)
+		require.NotNil(t, u.Disabled)
+		assert.False(t, *u.Disabled)
+		assert.Equal(t, []bool{true, false}, u.Flags)
+
+		bad, err := LoadSources(LoadOptions{BooleanStates: SystemdBooleanStates()}, []byte("Enabled = True1"))
+		require.NoError(t, err)
+		assert.Error(t, bad.StrictMapTo(u))
+	})
+}
+
+func TestBooleanStyle(t *testing.T) {
+	t.Run("default style", func(t *testing.T) {
+		f := Empty()
+		f.Section("").Key("a").SetBool(true)
+		f.Section("").Key("b").SetBool(false)
+		assert.Equal(t, "true", f.Section("").Key("a").String())
+		assert.Equal(t, "false", f.Section("").Key("b").String())
+	})
+
+	t.Run("set and must", func(t *testing.T) {
+		f := Empty(LoadOptions{BooleanStyle: BooleanStyleOnOff})
+		sec := f.Section("")
+		sec.Key("a").SetBool(true)
+		assert.Equal(t, "on", sec.Key("a").String())
+		assert.True(t, sec.Key("a").MustBool())
+
+		assert.False(t, sec.Key("missing").MustBool(false))
+		assert.Equal(t, "off", sec.Key("missing").String())
+	})
+
+	t.Run("reflect from struct", func(t *testing.T) {
+		type options struct {
+			Debug   bool   `ini:"debug"`
+			Verbose *bool  `ini:"verbose"`
+			Flags   []bool `ini:"flags"`
+			History []bool `ini:"history,,allowshadow"`
+		}
+		verbose := false
+		opts := &options{
+			Debug:   true,
+			Verbose: &verbose,
+			Flags:   []bool{true, false},
+			History: []bool{false, true},
+		}
+
+		f := Empty(LoadOptions{AllowShadows: true, BooleanStyle: BooleanStyleYesNo})
+		require.NoError(t, f.ReflectFrom(opts))
+
+		var buf bytes.Buffer
+		_, err := f.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, `debug   = yes
+verbose = no
+flags   = yes,no
+history = no
+history = yes
+`, buf.String())
+
+		// Written values are read back with the same vocabulary.
+		got := new(options)
+		require.NoError(t, f.StrictMapTo(got))
+		assert.Equal(t, opts, got)
+	})
+}
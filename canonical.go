@@ -0,0 +1,132 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortOptions contains comparators used for sorting sections and keys.
+type SortOptions struct {
+	// SectionLess reports whether a section name component should sort before another.
+	// Section names are compared component by component split by ChildSectionDelimiter,
+	// so child sections always follow their parent sections. The default section always
+	// sorts first. By default, components are sorted lexically.
+	SectionLess func(a, b string) bool
+	// KeyLess reports whether a key name should sort before another.
+	// By default, key names are sorted lexically.
+	KeyLess func(a, b string) bool
+}
+
+func lexicalLess(a, b string) bool {
+	return a < b
+}
+
+func (opts SortOptions) keyLess() func(a, b string) bool {
+	if opts.KeyLess != nil {
+		return opts.KeyLess
+	}
+	return lexicalLess
+}
+
+// sectionLess returns the comparator of full section names.
+func (f *File) sectionLess(opts SortOptions) func(a, b string) bool {
+	less := opts.SectionLess
+	if less == nil {
+		less = lexicalLess
+	}
+	delim := f.options.ChildSectionDelimiter
+	defaultName := f.sectionName(DefaultSection)
+
+	return func(a, b string) bool {
+		if a == defaultName || b == defaultName {
+			return a == defaultName && b != defaultName
+		}
+
+		as, bs := strings.Split(a, delim), strings.Split(b, delim)
+		for i := 0; i < len(as) && i < len(bs); i++ {
+			if as[i] == bs[i] {
+				continue
+			}
+			return less(as[i], bs[i])
+		}
+		return len(as) < len(bs)
+	}
+}
+
+// sortedSections returns copies of the section list and indexes in sorted order.
+// Sections with the same name keep their relative order.
+func (f *File) sortedSections(opts SortOptions) ([]string, []int) {
+	less := f.sectionLess(opts)
+	order := make([]int, len(f.sectionList))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return less(f.sectionList[order[i]], f.sectionList[order[j]])
+	})
+
+	list := make([]string, len(order))
+	indexes := make([]int, len(order))
+	for i, idx := range order {
+		list[i] = f.sectionList[idx]
+		indexes[i] = f.sectionIndexes[idx]
+	}
+	return list, indexes
+}
+
+// sortedKeys returns a sorted copy of the key list.
+func sortedKeys(keyList []string, less func(a, b string) bool) []string {
+	list := make([]string, len(keyList))
+	copy(list, keyList)
+	sort.SliceStable(list, func(i, j int) bool {
+		return less(list[i], list[j])
+	})
+	return list
+}
+
+// Canonicalize sorts sections and keys of the file in place with given comparators,
+// so that the output only depends on the content rather than load or insertion order.
+func (f *File) Canonicalize(opts ...SortOptions) {
+	var opt SortOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+
+	f.sectionList, f.sectionIndexes = f.sortedSections(opt)
+	keyLess := opt.keyLess()
+	for _, secs := range f.sections {
+		for _, sec := range secs {
+			sec.keyList = sortedKeys(sec.keyList, keyLess)
+		}
+	}
+}
+
+// CanonicalWriteOptions returns write options producing canonical output, which
+// does not depend on package-level variables, load or insertion order.
+func CanonicalWriteOptions() WriteOptions {
+	return WriteOptions{
+		SpaceAroundDelimiter: true,
+		LineBreak:            "\n",
+		SectionSpacing:       1,
+		Canonical:            true,
+	}
+}
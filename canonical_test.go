@@ -0,0 +1,176 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Canonicalize(t *testing.T) {
+	load := func(t *testing.T, data string) *File {
+		f, err := Load([]byte(data))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+		return f
+	}
+	write := func(t *testing.T, f *File, opts WriteOptions) string {
+		var buf bytes.Buffer
+		_, err := f.WriteToWithOptions(&buf, opts)
+		require.NoError(t, err)
+		return buf.String()
+	}
+
+	f1 := load(t, `
+[b]
+y = 2
+x = 1
+
+[ab]
+key = value
+
+[a.c]
+key = value
+
+[a]
+;   Parent section   
+name = a
+
+[a.b]
+key = value
+`)
+	f2 := load(t, `
+[a.b]
+key = value
+
+[a]
+; Parent section
+name = a
+
+[a.c]
+key = value
+
+[b]
+x = 1
+y = 2
+
+[ab]
+key = value
+`)
+	f2.Section("").Key("root").SetValue("true")
+	f1.Section("").Key("root").SetValue("true")
+
+	t.Run("canonical write mode", func(t *testing.T) {
+		expected := `root = true
+
+[a]
+; Parent section
+name = a
+
+[a.b]
+key = value
+
+[a.c]
+key = value
+
+[ab]
+key = value
+
+[b]
+x = 1
+y = 2
+`
+		assert.Equal(t, expected, write(t, f1, CanonicalWriteOptions()))
+		assert.Equal(t, expected, write(t, f2, CanonicalWriteOptions()))
+
+		// The order in the file is untouched.
+		assert.Equal(t, []string{DefaultSection, "b", "ab", "a.c", "a", "a.b"}, f1.SectionStrings())
+	})
+
+	t.Run("custom comparators", func(t *testing.T) {
+		opts := CanonicalWriteOptions()
+		opts.Sort = SortOptions{
+			SectionLess: func(a, b string) bool { return a > b },
+			KeyLess:     func(a, b string) bool { return a > b },
+		}
+		output := write(t, f2, opts)
+		assert.True(t, strings.HasPrefix(output, "root = true\n\n[b]\ny = 2\nx = 1\n\n[ab]\n"))
+		assert.True(t, strings.HasSuffix(output, "[a]\n; Parent section\nname = a\n\n[a.c]\nkey = value\n\n[a.b]\nkey = value\n"))
+	})
+
+	t.Run("canonicalize in place", func(t *testing.T) {
+		f1.Canonicalize()
+		assert.Equal(t, []string{DefaultSection, "a", "a.b", "a.c", "ab", "b"}, f1.SectionStrings())
+		assert.Equal(t, []string{"x", "y"}, f1.Section("b").KeyStrings())
+
+		// Comments are only normalized in canonical mode.
+		opts := CanonicalWriteOptions()
+		opts.Canonical = false
+		expected := strings.Replace(write(t, f2, CanonicalWriteOptions()), "; Parent section", ";   Parent section", 1)
+		assert.Equal(t, expected, write(t, f1, opts))
+	})
+
+	t.Run("default section sorts first with insensitive names", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{Insensitive: true}, []byte(`
+[b]
+x = 1
+
+[a]
+y = 2
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+		f.Section("").Key("root").SetValue("true")
+
+		expected := `root = true
+
+[a]
+y = 2
+
+[b]
+x = 1
+`
+		assert.Equal(t, expected, write(t, f, CanonicalWriteOptions()))
+
+		f.Canonicalize()
+		assert.Equal(t, []string{"default", "a", "b"}, f.SectionStrings())
+	})
+
+	t.Run("non-unique sections keep relative order", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{AllowNonUniqueSections: true}, []byte(`
+[peer]
+name = second-loaded-first
+
+[interface]
+name = wg0
+
+[peer]
+name = loaded-second
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		f.Canonicalize()
+		secs := f.Sections()
+		require.Len(t, secs, 4)
+		assert.Equal(t, "wg0", secs[1].Key("name").String())
+		assert.Equal(t, "second-loaded-first", secs[2].Key("name").String())
+		assert.Equal(t, "loaded-second", secs[3].Key("name").String())
+	})
+}
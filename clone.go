@@ -0,0 +1,198 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Clone returns a deep copy of the file, including its data sources, so that
+// changes to either of them do not affect the other. The keyring and mappers
+// are shared.
+func (f *File) Clone() *File {
+	if f.BlockMode {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
+	}
+	return f.clone()
+}
+
+// clone is like Clone. The caller must hold the lock.
+func (f *File) clone() *File {
+	sources := make([]dataSource, len(f.dataSources))
+	for i, s := range f.dataSources {
+		// Files track their checksums for detecting changes made by others.
+		if src, ok := s.(*sourceFile); ok {
+			cp := *src
+			s = &cp
+		}
+		sources[i] = s
+	}
+
+	c := f.emptyCopy(sources)
+	list := f.sectionsInOrder()
+	for i, sec := range list {
+		list[i] = sec.clone(c, sec.name)
+	}
+	c.setSections(list)
+	return c
+}
+
+// Subset returns a new file with deep copies of sections with given names only,
+// in their original order. The returned file has no data sources, and always
+// has the default section even if not chosen.
+func (f *File) Subset(names ...string) *File {
+	return f.subset(names, false)
+}
+
+// SubsetWithChildren is like Subset but also includes child sections of chosen
+// sections, e.g. "[parent.child]" for "parent".
+func (f *File) SubsetWithChildren(names ...string) *File {
+	return f.subset(names, true)
+}
+
+func (f *File) subset(names []string, withChildren bool) *File {
+	if f.BlockMode {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
+	}
+
+	chosen := make(map[string]bool, len(names))
+	for _, name := range names {
+		chosen[f.sectionName(name)] = true
+	}
+	isChosen := func(name string) bool {
+		if chosen[name] {
+			return true
+		}
+		if withChildren {
+			for parent := range chosen {
+				if strings.HasPrefix(name, parent+f.options.ChildSectionDelimiter) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	c := f.emptyCopy(nil)
+	defaultName := f.sectionName(DefaultSection)
+	list := make([]*Section, 0, len(chosen))
+	for _, sec := range f.sectionsInOrder() {
+		if isChosen(sec.name) {
+			list = append(list, sec.clone(c, sec.name))
+		}
+	}
+	if indexOfSection(list, defaultName) == -1 {
+		list = insertSection(list, 0, newSection(c, defaultName))
+	}
+	c.setSections(list)
+	return c
+}
+
+// emptyCopy returns a new file without sections but with the same options and
+// settings. The caller must hold the lock.
+func (f *File) emptyCopy(sources []dataSource) *File {
+	c := newFile(sources, f.options)
+	c.BlockMode = f.BlockMode
+	c.keyring = f.Keyring()
+	c.FooterComment = f.FooterComment
+	c.NameMapper = f.NameMapper
+	c.ValueMapper = f.ValueMapper
+	return c
+}
+
+// CopySection creates a new section with given name in the file, and copies the
+// comments, keys, shadows and nested values of the source section into it. The
+// source section may belong to another file. It returns an error if the section
+// already exists unless non-unique sections are allowed.
+func (f *File) CopySection(src *Section, newName string) (*Section, error) {
+	if len(newName) == 0 {
+		return nil, errors.New("empty section name")
+	}
+	spelling := newName
+	newName = f.sectionName(newName)
+
+	// Copy before locking the file, which may be the same as the source.
+	if src.f.BlockMode {
+		src.f.lock.RLock()
+	}
+	sec := src.clone(f, newName)
+	sec.spelling = spelling
+	if src.f.BlockMode {
+		src.f.lock.RUnlock()
+	}
+
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+
+	if !f.options.AllowNonUniqueSections && len(f.sections[newName]) > 0 {
+		return nil, fmt.Errorf("section %q already exists", newName)
+	}
+	f.sectionList = append(f.sectionList, newName)
+	f.sectionIndexes = append(f.sectionIndexes, len(f.sections[newName]))
+	f.sections[newName] = append(f.sections[newName], sec)
+	return sec, nil
+}
+
+// clone returns a deep copy of the section with given name belonging to given
+// file. Key names are adjusted to the options of the file. The caller must hold
+// the lock of the file of the section.
+func (s *Section) clone(f *File, name string) *Section {
+	c := newSection(f, name)
+	c.Comment = s.Comment
+	c.InlineComment = s.InlineComment
+	c.isRawSection = s.isRawSection
+	c.rawBody = s.rawBody
+	if name == s.name {
+		c.spelling = s.spelling
+	}
+	c.copyKeys(s)
+	return c
+}
+
+// copyKeys copies keys of given section into the section, replacing existing
+// keys with the same names in place. The caller must hold the lock of the file
+// of the given section.
+func (s *Section) copyKeys(src *Section) {
+	for _, kname := range src.keyList {
+		key := src.keys[kname].clone(s, s.keyName(kname))
+		if _, ok := s.keys[key.name]; !ok {
+			s.keyList = append(s.keyList, key.name)
+		}
+		s.keys[key.name] = key
+		s.keysHash[key.name] = key.value
+	}
+}
+
+// clone returns a deep copy of the key with given name belonging to given section.
+func (k *Key) clone(s *Section, name string) *Key {
+	c := *k
+	c.s = s
+	c.name = name
+	// Encrypted values are bound to the original key until re-encrypted.
+	c.secretPath = string(k.secretAAD())
+	c.rebindSecret()
+	c.nestedValues = append([]string(nil), k.nestedValues...)
+	c.shadows = make([]*Key, len(k.shadows))
+	for i, shadow := range k.shadows {
+		c.shadows[i] = shadow.clone(s, name)
+	}
+	return &c
+}
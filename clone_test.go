@@ -0,0 +1,164 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Clone(t *testing.T) {
+	f, err := LoadSources(LoadOptions{AllowShadows: true, AllowNestedValues: true}, []byte(`
+; Application
+name = app
+
+[server]
+; Listen address
+host = localhost ; inline
+port = 80
+port = 8080
+
+[aws]
+credentials =
+  region = us-east-1
+`))
+	require.NoError(t, err)
+
+	c := f.Clone()
+	var want, got bytes.Buffer
+	_, err = f.WriteTo(&want)
+	require.NoError(t, err)
+	_, err = c.WriteTo(&got)
+	require.NoError(t, err)
+	assert.Equal(t, want.String(), got.String())
+
+	// Keys belong to the clone
+	port := c.Section("server").Key("port")
+	assert.Equal(t, c.Section("server"), port.s)
+	for _, k := range port.withShadows() {
+		assert.Equal(t, c.Section("server"), k.s)
+	}
+
+	// Changes do not affect each other
+	c.Section("server").Key("host").SetValue("example.com")
+	require.NoError(t, c.Section("server").Key("port").AddShadow("9090"))
+	require.NoError(t, c.Section("aws").Key("credentials").AddNestedValue("output = json"))
+	c.DeleteSection("")
+	f.Section("server").Key("user").SetValue("root")
+
+	assert.Equal(t, "localhost", f.Section("server").Key("host").String())
+	assert.Equal(t, []string{"80", "8080"}, f.Section("server").Key("port").ValueWithShadows())
+	assert.Equal(t, []string{"region = us-east-1"}, f.Section("aws").Key("credentials").NestedValues())
+	assert.Equal(t, "app", f.Section("").Key("name").String())
+	assert.False(t, c.Section("server").HasKey("user"))
+	assert.Equal(t, []string{"80", "8080", "9090"}, c.Section("server").Key("port").ValueWithShadows())
+}
+
+func TestFile_Subset(t *testing.T) {
+	f, err := Load([]byte(`
+name = app
+[db]
+host = localhost
+[db.replica]
+host = replica
+[cache]
+size = 10
+[log]
+level = info
+`))
+	require.NoError(t, err)
+
+	t.Run("chosen sections only", func(t *testing.T) {
+		s := f.Subset("log", "db")
+		assert.Equal(t, []string{DefaultSection, "db", "log"}, s.SectionStrings())
+		assert.Empty(t, s.Section("").KeyStrings())
+		assert.Equal(t, "localhost", s.Section("db").Key("host").String())
+
+		s.Section("db").Key("host").SetValue("example.com")
+		assert.Equal(t, "localhost", f.Section("db").Key("host").String())
+	})
+
+	t.Run("with the default section", func(t *testing.T) {
+		s := f.Subset("", "cache")
+		assert.Equal(t, []string{DefaultSection, "cache"}, s.SectionStrings())
+		assert.Equal(t, "app", s.Section("").Key("name").String())
+	})
+
+	t.Run("with child sections", func(t *testing.T) {
+		s := f.SubsetWithChildren("db")
+		assert.Equal(t, []string{DefaultSection, "db", "db.replica"}, s.SectionStrings())
+		assert.Equal(t, "replica", s.Section("db.replica").Key("host").String())
+	})
+}
+
+func TestFile_CopySection(t *testing.T) {
+	t.Run("within the same file", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{AllowShadows: true, AllowNestedValues: true}, []byte(`
+[server]
+; Listen address
+host = localhost
+port = 80
+port = 8080
+[aws]
+credentials =
+  region = us-east-1
+`))
+		require.NoError(t, err)
+		f.BlockMode = true
+
+		sec, err := f.CopySection(f.Section("server"), "backup")
+		require.NoError(t, err)
+		assert.Equal(t, "backup", sec.Name())
+		assert.Equal(t, []string{DefaultSection, "server", "aws", "backup"}, f.SectionStrings())
+		assert.Equal(t, "; Listen address", sec.Key("host").Comment)
+
+		port := sec.Key("port")
+		assert.Equal(t, []string{"80", "8080"}, port.ValueWithShadows())
+		for _, k := range port.withShadows() {
+			assert.Equal(t, sec, k.s)
+		}
+
+		port.SetValue("443")
+		assert.Equal(t, "80", f.Section("server").Key("port").String())
+
+		aws, err := f.CopySection(f.Section("aws"), "aws.prod")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"region = us-east-1"}, aws.Key("credentials").NestedValues())
+
+		_, err = f.CopySection(f.Section("server"), "aws")
+		assert.Error(t, err)
+		_, err = f.CopySection(f.Section("server"), "")
+		assert.Error(t, err)
+	})
+
+	t.Run("from another file", func(t *testing.T) {
+		src, err := Load([]byte(`
+[Server]
+HOST = localhost
+`))
+		require.NoError(t, err)
+		f := Empty(LoadOptions{Insensitive: true})
+
+		sec, err := f.CopySection(src.Section("Server"), "Backup")
+		require.NoError(t, err)
+		assert.Equal(t, "backup", sec.Name())
+		assert.Equal(t, []string{"host"}, sec.KeyStrings())
+		assert.Equal(t, "localhost", f.Section("BACKUP").Key("Host").String())
+		assert.Equal(t, map[string]string{"host": "localhost"}, sec.KeysHash())
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Command ini inspects INI files.
+//
+// Usage:
+//
+//	ini query [flags] <expression> <file>...
+//
+// The query subcommand prints keys selected by the expression, see File.Query
+// for the syntax, one per line in the form of "section/key = value". Lines are
+// prefixed with the file name when more than one file is given. It exits with
+// status 1 if no key is selected, as grep does. Files that cannot be loaded
+// are reported on the standard error and skipped, and the command exits with
+// status 2 after querying the remaining files.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/ini.v1"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: ini query [flags] <expression> <file>...")
+}
+
+// run runs the command with given arguments and returns the exit status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "query" {
+		usage(stderr)
+		return 2
+	}
+
+	flags := flag.NewFlagSet("query", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		usage(stderr)
+		flags.PrintDefaults()
+	}
+	insensitive := flags.Bool("insensitive", false, "match section and key names case-insensitively")
+	shadows := flags.Bool("shadows", false, "allow shadow keys")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if flags.NArg() < 2 {
+		flags.Usage()
+		return 2
+	}
+
+	expr, files := flags.Arg(0), flags.Args()[1:]
+	opts := ini.LoadOptions{
+		Insensitive:  *insensitive,
+		AllowShadows: *shadows,
+	}
+	// Report invalid expressions even if no file can be loaded.
+	if _, err := ini.Empty(opts).Query(expr); err != nil {
+		fmt.Fprintf(stderr, "ini: %v\n", err)
+		return 2
+	}
+
+	status, failed := 1, false
+	for _, name := range files {
+		f, err := ini.LoadSources(opts, name)
+		if err != nil {
+			fmt.Fprintf(stderr, "ini: %s: %v\n", name, err)
+			failed = true
+			continue
+		}
+		// The expression is valid as checked above.
+		keys, _ := f.Query(expr)
+
+		for _, key := range keys {
+			status = 0
+			if len(files) > 1 {
+				fmt.Fprintf(stdout, "%s:", name)
+			}
+			fmt.Fprintf(stdout, "%s/%s = %s\n", key.Section().Name(), key.Name(), key.String())
+		}
+	}
+	if failed {
+		return 2
+	}
+	return status
+}
@@ -0,0 +1,78 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ini")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	web := filepath.Join(dir, "web.ini")
+	require.NoError(t, ioutil.WriteFile(web, []byte("[servers.a]\nport = 80\n[servers.b]\nport = 81\n"), 0644))
+	db := filepath.Join(dir, "db.ini")
+	require.NoError(t, ioutil.WriteFile(db, []byte("[db]\nport = 5432\n"), 0644))
+
+	t.Run("single file", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 0, run([]string{"query", "servers.*.port", web}, &stdout, &stderr))
+		assert.Equal(t, "servers.a/port = 80\nservers.b/port = 81\n", stdout.String())
+	})
+
+	t.Run("multiple files", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 0, run([]string{"query", "-insensitive", "[*]/PORT", web, db}, &stdout, &stderr))
+		assert.Equal(t, web+":servers.a/port = 80\n"+web+":servers.b/port = 81\n"+db+":db/port = 5432\n", stdout.String())
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 1, run([]string{"query", "[*]/host", web}, &stdout, &stderr))
+		assert.Empty(t, stdout.String())
+	})
+
+	t.Run("continue after load errors", func(t *testing.T) {
+		invalid := filepath.Join(dir, "invalid.ini")
+		require.NoError(t, ioutil.WriteFile(invalid, []byte("[invalid\n"), 0644))
+		missing := filepath.Join(dir, "missing.ini")
+
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 2, run([]string{"query", "db/port", invalid, missing, db}, &stdout, &stderr))
+		assert.Equal(t, db+":db/port = 5432\n", stdout.String())
+		assert.Contains(t, stderr.String(), invalid)
+		assert.Contains(t, stderr.String(), missing)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		assert.Equal(t, 2, run(nil, &stdout, &stderr))
+		assert.Equal(t, 2, run([]string{"query", "[*]/port"}, &stdout, &stderr))
+		assert.Equal(t, 2, run([]string{"query", "[*", web}, &stdout, &stderr))
+		stderr.Reset()
+		assert.Equal(t, 2, run([]string{"query", "[*", filepath.Join(dir, "missing.ini")}, &stdout, &stderr))
+		assert.NotContains(t, stderr.String(), "missing.ini")
+		assert.Equal(t, 2, run([]string{"query", "port", filepath.Join(dir, "missing.ini")}, &stdout, &stderr))
+	})
+}
@@ -16,7 +16,9 @@ package ini
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
+	"hash"
 	"io"
 	"io/ioutil"
 	"os"
@@ -36,10 +38,70 @@ type dataSource interface {
 // sourceFile represents an object that contains content on the local file system.
 type sourceFile struct {
 	name string
+
+	// Whether the file existed and the checksum of its content when last read,
+	// used to detect changes made by others.
+	exists   bool
+	checksum [sha256.Size]byte
+}
+
+func (s *sourceFile) ReadCloser() (io.ReadCloser, error) {
+	file, err := os.Open(s.name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			s.exists = false
+		}
+		return nil, err
+	}
+
+	s.exists = true
+	hash := sha256.New()
+	return &checksumReadCloser{
+		Reader: io.TeeReader(file, hash),
+		file:   file,
+		hash:   hash,
+		src:    s,
+	}, nil
+}
+
+// checksumReadCloser computes the checksum of the content of a file while it
+// is being read, and records it to the data source when closed.
+type checksumReadCloser struct {
+	io.Reader
+	file *os.File
+	hash hash.Hash
+	src  *sourceFile
 }
 
-func (s sourceFile) ReadCloser() (_ io.ReadCloser, err error) {
-	return os.Open(s.name)
+func (r *checksumReadCloser) Close() error {
+	// Read the rest in case the parser stops early, so that the checksum always
+	// covers the whole content.
+	_, err := io.Copy(ioutil.Discard, r.Reader)
+	copy(r.src.checksum[:], r.hash.Sum(nil))
+	if cerr := r.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// fileChecksum returns the checksum of the content of given file, and false if
+// it does not exist.
+func fileChecksum(name string) (sum [sha256.Size]byte, exists bool, err error) {
+	file, err := os.Open(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sum, false, nil
+		}
+		return sum, false, err
+	}
+	defer func() { _ = file.Close() }()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return sum, false, err
+	}
+	copy(sum[:], hash.Sum(nil))
+	return sum, true, nil
 }
 
 // sourceData represents an object that contains content in memory.
@@ -63,7 +125,7 @@ func (s *sourceReadCloser) ReadCloser() (io.ReadCloser, error) {
 func parseDataSource(source interface{}) (dataSource, error) {
 	switch s := source.(type) {
 	case string:
-		return sourceFile{s}, nil
+		return &sourceFile{name: s}, nil
 	case []byte:
 		return &sourceData{s}, nil
 	case io.ReadCloser:
@@ -0,0 +1,207 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+// Package editorconfig resolves EditorConfig properties of files.
+// Docs: https://spec.editorconfig.org
+package editorconfig
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/ini.v1"
+)
+
+// DefaultConfigName is the default name of EditorConfig files.
+const DefaultConfigName = ".editorconfig"
+
+// The byte order mark of UTF-8 at the beginning of files.
+var utf8BOM = []byte{0xef, 0xbb, 0xbf}
+
+// Maximum allowed length of a section name, longer ones are ignored.
+const maxSectionNameLength = 4096
+
+// Properties whose values are case-insensitive and lowercased when resolving.
+var lowercaseProperties = map[string]bool{
+	"end_of_line":              true,
+	"indent_style":             true,
+	"indent_size":              true,
+	"insert_final_newline":     true,
+	"trim_trailing_whitespace": true,
+	"charset":                  true,
+}
+
+// Options contains all customized options used for resolving properties.
+type Options struct {
+	// ConfigName is the name of EditorConfig files. By default, it is ".editorconfig".
+	ConfigName string
+}
+
+// loadOptions returns options for loading EditorConfig files with the ini package.
+func loadOptions() ini.LoadOptions {
+	return ini.LoadOptions{
+		InsensitiveKeys:          true,
+		IgnoreContinuation:       true,
+		SpaceBeforeInlineComment: true,
+		SkipUnrecognizableLines:  true,
+		PreserveSurroundedQuote:  true,
+		AllowNonUniqueSections:   true,
+		KeyValueDelimiters:       "=",
+	}
+}
+
+// configFile is a loaded EditorConfig file.
+type configFile struct {
+	dir  string
+	file *ini.File
+}
+
+// loadConfigFiles loads EditorConfig files from the root directory to the
+// directory of given file, stopping at the nearest file declaring itself as root.
+func loadConfigFiles(filename, configName string) ([]configFile, error) {
+	var files []configFile
+	dir := filepath.Dir(filename)
+	for {
+		path := filepath.Join(dir, configName)
+		if data, err := ioutil.ReadFile(path); err == nil {
+			f, err := ini.LoadSources(loadOptions(), stripSectionComments(data))
+			if err != nil {
+				return nil, fmt.Errorf("load %q: %v", path, err)
+			}
+			files = append(files, configFile{dir: dir, file: f})
+
+			if strings.EqualFold(f.Section("").Key("root").Value(), "true") {
+				break
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	// Reverse so that the closer files take precedence.
+	for i, j := 0, len(files)-1; i < j; i, j = i+1, j-1 {
+		files[i], files[j] = files[j], files[i]
+	}
+	return files, nil
+}
+
+// stripSectionComments removes comments after closing brackets of section
+// headers, e.g. "[*.c] ; comment", which may contain "]" themselves.
+func stripSectionComments(data []byte) []byte {
+	lines := bytes.Split(bytes.TrimPrefix(data, utf8BOM), []byte("\n"))
+	for i, line := range lines {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] != '[' {
+			continue
+		}
+
+		for j := 1; j < len(line); j++ {
+			if (line[j] == ';' || line[j] == '#') && (line[j-1] == ' ' || line[j-1] == '\t') {
+				if header := bytes.TrimSpace(line[:j]); header[len(header)-1] == ']' {
+					lines[i] = header
+				}
+				break
+			}
+		}
+	}
+	return bytes.Join(lines, []byte("\n"))
+}
+
+// sectionGlob returns the glob pattern of section name which matches paths
+// relative to the directory of the EditorConfig file.
+func sectionGlob(dir, name string) string {
+	dir = escapeGlob(filepath.ToSlash(dir))
+	if strings.Contains(name, "/") {
+		return strings.TrimSuffix(dir, "/") + "/" + strings.TrimPrefix(name, "/")
+	}
+	return strings.TrimSuffix(dir, "/") + "/**/" + name
+}
+
+// Resolve returns the EditorConfig properties of given file. Names of
+// properties are lowercased, so are values of properties defined by the
+// specification. The file does not have to exist.
+func Resolve(filename string, opts ...Options) (map[string]string, error) {
+	var opt Options
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	if opt.ConfigName == "" {
+		opt.ConfigName = DefaultConfigName
+	}
+
+	filename, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	files, err := loadConfigFiles(filename, opt.ConfigName)
+	if err != nil {
+		return nil, err
+	}
+
+	target := filepath.ToSlash(filename)
+	props := make(map[string]string)
+	for _, cf := range files {
+		for _, sec := range cf.file.Sections() {
+			if sec.Name() == ini.DefaultSection || len(sec.Name()) > maxSectionNameLength {
+				continue
+			}
+
+			g, err := compileGlob(sectionGlob(cf.dir, sec.Name()))
+			if err != nil || !g.match(target) {
+				continue
+			}
+			for _, key := range sec.Keys() {
+				props[key.Name()] = key.Value()
+			}
+		}
+	}
+
+	for name, value := range props {
+		if lowercaseProperties[name] {
+			props[name] = strings.ToLower(value)
+		}
+	}
+	applyDefaults(props)
+	return props, nil
+}
+
+// applyDefaults sets implied values of "indent_size" and "tab_width".
+func applyDefaults(props map[string]string) {
+	if props["indent_style"] == "tab" {
+		if _, ok := props["indent_size"]; !ok {
+			props["indent_size"] = "tab"
+		}
+	}
+
+	tabWidth, hasTabWidth := props["tab_width"]
+	indentSize, hasIndentSize := props["indent_size"]
+	if indentSize == "tab" && hasTabWidth {
+		props["indent_size"] = tabWidth
+	} else if hasIndentSize && !hasTabWidth {
+		if _, err := strconv.Atoi(indentSize); err == nil {
+			props["tab_width"] = indentSize
+		}
+	}
+}
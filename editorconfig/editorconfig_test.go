@@ -0,0 +1,279 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package editorconfig
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test cases reproduce the EditorConfig core test suite, named after its test
+// files with ".in" configuration files under testdata. The corpus itself is not
+// vendored. Cases deliberately unsupported are:
+//   - cli: the command line interface, where "-f" is Options.ConfigName and
+//     multiple files are resolved by calling Resolve for each of them.
+//   - cli "-b" and properties "*_pre_0_9_0": behavior of versions before 0.9.0.
+//   - parser limits of property names and values, only section names are limited.
+//   - filetree "windows_separator*" with backslashes in paths on Windows.
+//
+// Docs: https://github.com/editorconfig/editorconfig-core-test
+
+type resolveCase struct {
+	path string
+	want map[string]string
+}
+
+func testResolve(t *testing.T, dir, configName string, cases []resolveCase) {
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			if runtime.GOOS == "windows" && strings.ContainsAny(tc.path, `\\*?`) {
+				t.Skip("Skipping invalid file name on Windows")
+			}
+
+			got, err := Resolve(filepath.Join("testdata", dir, tc.path), Options{ConfigName: configName})
+			require.NoError(t, err)
+			if tc.want == nil {
+				tc.want = map[string]string{}
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestResolve_GlobStar(t *testing.T) {
+	testResolve(t, "glob", "star.in", []resolveCase{
+		{"a1e.c", map[string]string{"key": "value", "keyc": "valuec"}},
+		{"ae.c", map[string]string{"key": "value", "keyc": "valuec"}},
+		{"a11e.c", map[string]string{"key": "value", "keyc": "valuec"}},
+		{"sub/a1e.c", map[string]string{"key": "value", "keyc": "valuec"}},
+		{"a/e.c", map[string]string{"keyc": "valuec"}},
+		{"Bar/foo.txt", map[string]string{"keyb": "valueb", "keyc": "valuec"}},
+		{"Bar/foo/baz.txt", map[string]string{"keyc": "valuec"}},
+	})
+}
+
+func TestResolve_GlobQuestion(t *testing.T) {
+	testResolve(t, "glob", "question.in", []resolveCase{
+		{"some.c", map[string]string{"key": "value"}},
+		{"som.c", nil},
+		{"something.c", nil},
+		{"som/.c", nil},
+	})
+}
+
+func TestResolve_GlobBrackets(t *testing.T) {
+	testResolve(t, "glob", "brackets.in", []resolveCase{
+		{"a.a", map[string]string{"choice": "true"}},
+		{"c.a", nil},
+		{"c.b", map[string]string{"choice": "false"}},
+		{"a.b", nil},
+		{"f.c", map[string]string{"range": "true"}},
+		{"h.c", nil},
+		{"h.d", map[string]string{"range": "false"}},
+		{"f.d", nil},
+		{"a.e", map[string]string{"range_and_choice": "true"}},
+		{"e.e", map[string]string{"range_and_choice": "true"}},
+		{"c.e", nil},
+		{"-.f", map[string]string{"choice_with_dash": "true"}},
+		{"].g", map[string]string{"close_inside": "true"}},
+		{"b].g", map[string]string{"close_outside": "true"}},
+		{"c.g", map[string]string{"close_inside": "false"}},
+		{"c].g", map[string]string{"close_outside": "false"}},
+		{"ab[e/]cd.i", map[string]string{"slash_inside": "true"}},
+		{"abecd.i", nil},
+		{"ab[/c", map[string]string{"slash_half_open": "true"}},
+	})
+}
+
+func TestResolve_GlobBraces(t *testing.T) {
+	testResolve(t, "glob", "braces.in", []resolveCase{
+		{"test.py", map[string]string{"choice": "true"}},
+		{"test.js", map[string]string{"choice": "true"}},
+		{"test.html", map[string]string{"choice": "true"}},
+		{"test.pyc", nil},
+		{"{single}.b", map[string]string{"choice": "single"}},
+		{"single.b", nil},
+		{"{}.c", map[string]string{"empty": "all"}},
+		{".c", nil},
+		{"a.d", map[string]string{"empty": "word"}},
+		{"ab.d", map[string]string{"empty": "word"}},
+		{"ac.d", map[string]string{"empty": "word"}},
+		{"a,.d", nil},
+		{"a.e", map[string]string{"empty": "words"}},
+		{"ab.e", map[string]string{"empty": "words"}},
+		{"ac.e", map[string]string{"empty": "words"}},
+		{"a,.e", nil},
+		{"{.f", map[string]string{"closing": "false"}},
+		{"word.g", map[string]string{"nested": "true"}},
+		{"{also}.g", map[string]string{"nested": "true"}},
+		{"this.g", map[string]string{"nested": "true"}},
+		{"a.k", map[string]string{"nested_start": "true"}},
+		{"b.k", map[string]string{"nested_start": "true"}},
+		{"c.k", map[string]string{"nested_start": "true"}},
+		{"a.l", map[string]string{"nested_end": "true"}},
+		{"c.l", map[string]string{"nested_end": "true"}},
+		{"{},b}.h", map[string]string{"closing": "inside"}},
+		{"{.i", map[string]string{"closing": "inside"}},
+		{"{b.i", map[string]string{"closing": "inside"}},
+		{"{c.i", map[string]string{"closing": "inside"}},
+		{"a,b.txt", map[string]string{"comma": "yes"}},
+		{"a.txt", nil},
+		{"cd.txt", map[string]string{"comma": "yes"}},
+		{"}.txt", map[string]string{"closing": "yes"}},
+		{`\.txt`, map[string]string{"backslash": "yes"}},
+		{"some.j", map[string]string{"patterns": "nested"}},
+		{"abe.j", map[string]string{"patterns": "nested"}},
+		{"abxcf.j", map[string]string{"patterns": "nested"}},
+		{"abxcg.j", nil},
+		{"1", nil},
+		{"3", map[string]string{"number": "true"}},
+		{"15", map[string]string{"number": "true"}},
+		{"120", map[string]string{"number": "true"}},
+		{"121", nil},
+		{"060", nil},
+		{"aardvark", nil},
+		{"{aardvark..antelope}", map[string]string{"words": "a"}},
+	})
+}
+
+func TestResolve_GlobStarStar(t *testing.T) {
+	testResolve(t, "glob", "star_star.in", []resolveCase{
+		{"az.c", map[string]string{"key1": "value1"}},
+		{"amnz.c", map[string]string{"key1": "value1"}},
+		{"am/nz.c", map[string]string{"key1": "value1"}},
+		{"a/mnz.c", map[string]string{"key1": "value1"}},
+		{"amn/z.c", map[string]string{"key1": "value1"}},
+		{"a/mn/z.c", map[string]string{"key1": "value1"}},
+		{"b/z.c", map[string]string{"key2": "value2"}},
+		{"b/mnz.c", map[string]string{"key2": "value2"}},
+		{"b/mn/z.c", map[string]string{"key2": "value2"}},
+		{"c/z.c", map[string]string{"key3": "value3"}},
+		{"cmn/z.c", map[string]string{"key3": "value3"}},
+		{"c/mn/z.c", map[string]string{"key3": "value3"}},
+		{"d/z.c", map[string]string{"key4": "value4"}},
+		{"d/mn/z.c", map[string]string{"key4": "value4"}},
+		{"d/m/n/z.c", map[string]string{"key4": "value4"}},
+		{"dz.c", nil},
+	})
+}
+
+func TestResolve_GlobPath(t *testing.T) {
+	testResolve(t, "glob", "path.in", []resolveCase{
+		{"path/separator", map[string]string{"key1": "value1"}},
+		{"path/separator/extra", nil},
+		{"top/of/path", map[string]string{"key2": "value2"}},
+		{"top/top/of/path", nil},
+		{"windows/separator", nil},
+	})
+}
+
+func TestResolve_ParserWhitespace(t *testing.T) {
+	testResolve(t, "parser", "whitespace.in", []resolveCase{
+		{"test1.c", map[string]string{"key": "value"}},
+		{"test2.c", map[string]string{"key": "value"}},
+		{"test3.c", map[string]string{"key": "value"}},
+		{"test4.c", map[string]string{"key": "value"}},
+		{"test5.c", map[string]string{"key": "value"}},
+		{"test6.c", map[string]string{"key1": "value1", "key2": "value2"}},
+		{" test 7 ", map[string]string{"key": "value"}},
+		{"test8.c", map[string]string{"key": "value"}},
+		{"test9.c", map[string]string{"key": "value"}},
+		{"test10.c", map[string]string{"key1": "value1", "key2": "value2", "key3": "value3"}},
+		{"test11.c", map[string]string{"key": "value with spaces"}},
+	})
+}
+
+func TestResolve_ParserComments(t *testing.T) {
+	testResolve(t, "parser", "comments.in", []resolveCase{
+		{"test1.c", map[string]string{"key": "value"}},
+		{"test2.c", map[string]string{"key": "value"}},
+		{"test3.c", map[string]string{"key": "value"}},
+		{"test4.c", map[string]string{"key": "value"}},
+		{"test5.c", map[string]string{"key": "value"}},
+		{"test6.c", map[string]string{"key": "value"}},
+		{"test7.c", map[string]string{"key": "value; Not comment: no whitespace before semicolon"}},
+		{"test8.c", map[string]string{"key": "value# Not comment: no whitespace before pound sign"}},
+	})
+}
+
+func TestResolve_ParserBOM(t *testing.T) {
+	testResolve(t, "parser", "bom.in", []resolveCase{
+		{"a.c", map[string]string{"key": "value"}},
+	})
+}
+
+func TestResolve_ParserLimits(t *testing.T) {
+	testResolve(t, "parser", "limits.in", []resolveCase{
+		{strings.Repeat("a", 4096), map[string]string{"key": "ok"}},
+		{strings.Repeat("b", 4097), nil},
+	})
+}
+
+func TestResolve_GlobUTF8(t *testing.T) {
+	testResolve(t, "glob", "utf8char.in", []resolveCase{
+		{"中文.txt", map[string]string{"key": "value"}},
+		{"中.txt", nil},
+	})
+}
+
+func TestResolve_FileTree(t *testing.T) {
+	testResolve(t, "filetree", "filetree.in", []resolveCase{
+		{"path/separator", map[string]string{"key": "value"}},
+		{"nested/path/separator", nil},
+		{"test.a", map[string]string{"key": "parent"}},
+		{"parent_directory/test.a", map[string]string{"key": "parent"}},
+		{"parent_directory/test.b", map[string]string{"key": "child"}},
+		{"root_file/test.a", map[string]string{"child": "value"}},
+		{"root_file_mixed_case/test.a", map[string]string{"child": "value"}},
+	})
+}
+
+func TestResolve_Properties(t *testing.T) {
+	testResolve(t, "properties", "properties.in", []resolveCase{
+		{"lowercase_names.c", map[string]string{"testproperty": "testValue", "testproperty2": "TestValue2", "override": "all"}},
+		{"lowercase_values.c", map[string]string{
+			"end_of_line":              "crlf",
+			"indent_style":             "space",
+			"charset":                  "utf-8",
+			"insert_final_newline":     "true",
+			"trim_trailing_whitespace": "false",
+			"custom":                   "MixedCase",
+			"override":                 "all",
+		}},
+		{"indent_size_default.c", map[string]string{"indent_style": "tab", "indent_size": "tab", "override": "all"}},
+		{"indent_size_default_with_tab_width.c", map[string]string{"indent_style": "tab", "indent_size": "2", "tab_width": "2", "override": "all"}},
+		{"tab_width_default.c", map[string]string{"indent_size": "4", "tab_width": "4", "override": "all"}},
+		{"indent_size_tab.c", map[string]string{"indent_size": "8", "tab_width": "8", "override": "all"}},
+		{"duplicate.c", map[string]string{"key": "second", "override": "all"}},
+	})
+}
+
+func TestResolve_Precedence(t *testing.T) {
+	testResolve(t, "precedence", "precedence.in", []resolveCase{
+		{"file.txt", map[string]string{"key1": "root", "key2": "root"}},
+		{"sub/file.txt", map[string]string{"key1": "root", "key2": "sub", "key3": "root"}},
+	})
+
+	t.Run("no configuration file", func(t *testing.T) {
+		got, err := Resolve(filepath.Join("testdata", "file.txt"), Options{ConfigName: "nonexistent.in"})
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+}
@@ -0,0 +1,233 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package editorconfig
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var numericRange = regexp.MustCompile(`^([+-]?\d+)\.\.([+-]?\d+)$`)
+
+// glob is a compiled EditorConfig glob pattern.
+type glob struct {
+	re *regexp.Regexp
+	// Inclusive bounds of numeric ranges in the order of their capture groups.
+	ranges [][2]int
+}
+
+// compileGlob compiles the EditorConfig glob pattern into a regular expression
+// matching the whole path.
+// Docs: https://spec.editorconfig.org/#glob-expressions
+func compileGlob(pattern string) (*glob, error) {
+	g := &glob{}
+	re, err := regexp.Compile("^" + g.translate(pattern) + "$")
+	if err != nil {
+		return nil, err
+	}
+	g.re = re
+	return g, nil
+}
+
+// match returns true if the path matches the pattern, and all numbers matched
+// by numeric ranges are within bounds.
+func (g *glob) match(path string) bool {
+	m := g.re.FindStringSubmatch(path)
+	if m == nil {
+		return false
+	}
+
+	for i, r := range g.ranges {
+		// The range is in an alternative not taken.
+		if m[i+1] == "" {
+			continue
+		}
+		n, err := strconv.Atoi(m[i+1])
+		if err != nil || n < r[0] || n > r[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// findClosingBrace returns the index of the brace closing the one at the start
+// of pattern, or -1 if there is none.
+func findClosingBrace(pattern string) int {
+	depth := 0
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitAlternatives splits the content of braces by commas which are not
+// escaped or nested in other braces.
+func splitAlternatives(content string) []string {
+	var alts []string
+	depth, start := 0, 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\\':
+			i++
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				alts = append(alts, content[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(alts, content[start:])
+}
+
+// translateBrackets translates the bracket expression at the start of pattern,
+// and returns the regular expression with the number of bytes consumed. It
+// returns zero length when the bracket should be treated literally.
+func translateBrackets(pattern string) (string, int) {
+	var buf strings.Builder
+	buf.WriteByte('[')
+
+	i := 1
+	if i < len(pattern) && (pattern[i] == '!' || pattern[i] == '^') {
+		buf.WriteByte('^')
+		i++
+	}
+	for ; i < len(pattern); i++ {
+		switch pattern[i] {
+		case ']':
+			if i == 1 {
+				return "", 0
+			}
+			buf.WriteByte(']')
+			return buf.String(), i + 1
+		case '/':
+			// Slashes are never matched by bracket expressions.
+			return "", 0
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			}
+		case '-':
+			buf.WriteByte('-')
+		default:
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	return "", 0
+}
+
+// translate converts the EditorConfig glob pattern to a regular expression.
+func (g *glob) translate(pattern string) string {
+	var buf strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch pattern[i] {
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+			} else {
+				buf.WriteString(`\\`)
+			}
+		case '/':
+			// "/**/" matches zero or more directories.
+			if strings.HasPrefix(pattern[i:], "/**/") {
+				buf.WriteString("(?:/|/.*/)")
+				i += 3
+			} else {
+				buf.WriteByte('/')
+			}
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				buf.WriteString(".*")
+				i++
+			} else {
+				buf.WriteString("[^/]*")
+			}
+		case '?':
+			buf.WriteString("[^/]")
+		case '[':
+			re, n := translateBrackets(pattern[i:])
+			if n == 0 {
+				buf.WriteString(`\[`)
+				continue
+			}
+			buf.WriteString(re)
+			i += n - 1
+		case '{':
+			end := findClosingBrace(pattern[i:])
+			if end == -1 {
+				buf.WriteString(`\{`)
+				continue
+			}
+			content := pattern[i+1 : i+end]
+			i += end
+
+			if m := numericRange.FindStringSubmatch(content); m != nil {
+				lo, _ := strconv.Atoi(m[1])
+				hi, _ := strconv.Atoi(m[2])
+				if lo > hi {
+					lo, hi = hi, lo
+				}
+				g.ranges = append(g.ranges, [2]int{lo, hi})
+				buf.WriteString(`([+-]?(?:0|[1-9][0-9]*))`)
+				continue
+			}
+
+			alts := splitAlternatives(content)
+			if len(alts) == 1 {
+				buf.WriteString(`\{` + g.translate(content) + `\}`)
+				continue
+			}
+			buf.WriteString("(?:")
+			for j, alt := range alts {
+				if j > 0 {
+					buf.WriteByte('|')
+				}
+				buf.WriteString(g.translate(alt))
+			}
+			buf.WriteString(")")
+		default:
+			buf.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	return buf.String()
+}
+
+// escapeGlob escapes characters having special meanings in glob patterns.
+func escapeGlob(s string) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(`\*?[]{},`, s[i]) > -1 {
+			buf.WriteByte('\\')
+		}
+		buf.WriteByte(s[i])
+	}
+	return buf.String()
+}
@@ -0,0 +1,256 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Encoder writes sections, keys and comments to an io.Writer as they are given,
+// without building a File in memory. Values are quoted by the same rules used
+// for writing files. Content is buffered, call Flush after the last write.
+//
+// Keys written before the first section belong to the default section. Unlike
+// writing a File, keys are not aligned even if WriteOptions.AlignEquals is set
+// because keys of a section are not known in advance.
+type Encoder struct {
+	w    *bufio.Writer
+	opts WriteOptions
+
+	lineBreak     string
+	commentPrefix string
+	equalSign     string
+	delimiters    string
+
+	section   string
+	inSection bool
+	wroteAny  bool
+	comments  []string
+	inline    string
+	err       error
+}
+
+// NewEncoder returns a new encoder that writes to w with given write options.
+func NewEncoder(w io.Writer, opts WriteOptions) *Encoder {
+	e := &Encoder{
+		w:             bufio.NewWriter(w),
+		opts:          opts,
+		lineBreak:     opts.LineBreak,
+		commentPrefix: opts.CommentPrefix,
+		section:       DefaultSection,
+	}
+	if len(e.lineBreak) == 0 {
+		e.lineBreak = LineBreak
+	}
+	if len(e.commentPrefix) == 0 {
+		e.commentPrefix = "; "
+	}
+
+	delimiter := opts.KeyValueDelimiter
+	if len(delimiter) == 0 {
+		delimiter = "="
+	}
+	e.equalSign = opts.DelimiterLeft + delimiter + opts.DelimiterRight
+	if opts.AlignEquals || opts.SpaceAroundDelimiter {
+		e.equalSign = " " + delimiter + " "
+	}
+	e.delimiters = "=:" + delimiter
+	return e
+}
+
+// Comment adds the comment before the next section or key. Comments that are
+// not followed by any section or key are written by Flush.
+func (e *Encoder) Comment(comment string) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.comments = append(e.comments, commentLines(comment, e.commentPrefix, e.opts.Canonical)...)
+	return nil
+}
+
+// InlineComment sets the comment written at the end of the next section header or key.
+func (e *Encoder) InlineComment(comment string) error {
+	if e.err != nil {
+		return e.err
+	}
+	if strings.ContainsAny(comment, "\r\n") {
+		return fmt.Errorf("inline comment %q contains line break", comment)
+	}
+	e.inline = comment
+	return nil
+}
+
+// Section starts a new section with given name, keys written after belong to it.
+func (e *Encoder) Section(name string) error {
+	if e.err != nil {
+		return e.err
+	}
+	if err := validateSectionName(name); err != nil {
+		return err
+	}
+
+	if e.wroteAny {
+		e.writeString(strings.Repeat(e.lineBreak, e.opts.SectionSpacing))
+	}
+	e.writeComments("")
+	e.writeString("[" + name + "]" + inlineComment(e.inline, e.commentPrefix) + e.lineBreak)
+	e.inline = ""
+	e.section = name
+	e.inSection = true
+	e.wroteAny = true
+	return e.err
+}
+
+// RawSection writes a section with given name whose body is written as-is.
+// The body is validated to not contain lines that would start a new section.
+func (e *Encoder) RawSection(name, body string) error {
+	if e.err != nil {
+		return e.err
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			return fmt.Errorf("raw section %q: line %q would start a new section", name, line)
+		}
+	}
+
+	if err := e.Section(name); err != nil {
+		return err
+	}
+	e.writeString(body)
+	if len(body) > 0 && !strings.HasSuffix(body, "\n") {
+		e.writeString(e.lineBreak)
+	}
+	return e.err
+}
+
+// Key writes a key with given name and value to the current section.
+func (e *Encoder) Key(name, value string) error {
+	if e.err != nil {
+		return e.err
+	}
+	if err := validateKeyName(name, e.delimiters); err != nil {
+		return err
+	}
+	if err := validateValue(value); err != nil {
+		return fmt.Errorf("key %q: %v", name, err)
+	}
+
+	indent := e.indent()
+	e.writeComments(indent)
+	e.writeString(indent + quoteKeyName(name, e.delimiters) + e.equalSign +
+		quoteValue(value, e.opts.Quote, true) + inlineComment(e.inline, e.commentPrefix) + e.lineBreak)
+	e.inline = ""
+	e.wroteAny = true
+	return e.err
+}
+
+// BooleanKey writes a key without value to the current section, which is
+// read back as true with LoadOptions.AllowBooleanKeys.
+func (e *Encoder) BooleanKey(name string) error {
+	if e.err != nil {
+		return e.err
+	}
+	if err := validateKeyName(name, e.delimiters); err != nil {
+		return err
+	}
+	// Boolean keys are read back as the whole line, thus cannot be quoted.
+	if quoteKeyName(name, e.delimiters) != name {
+		return fmt.Errorf("boolean key %q needs to be quoted", name)
+	}
+
+	indent := e.indent()
+	e.writeComments(indent)
+	e.writeString(indent + name + inlineComment(e.inline, e.commentPrefix) + e.lineBreak)
+	e.inline = ""
+	e.wroteAny = true
+	return e.err
+}
+
+// Flush writes pending comments and any buffered content to the underlying writer.
+func (e *Encoder) Flush() error {
+	if e.err != nil {
+		return e.err
+	}
+	e.writeComments("")
+	if e.err == nil {
+		e.err = e.w.Flush()
+	}
+	return e.err
+}
+
+// indent returns the indention of keys and comments in the current section.
+func (e *Encoder) indent() string {
+	if !e.inSection || e.section == DefaultSection {
+		return ""
+	}
+	return e.opts.Indent
+}
+
+func (e *Encoder) writeComments(indent string) {
+	for _, line := range e.comments {
+		e.writeString(indent + line + e.lineBreak)
+	}
+	e.comments = e.comments[:0]
+}
+
+// writeString writes s unless there was an error, which is kept to be returned
+// by all subsequent calls.
+func (e *Encoder) writeString(s string) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.WriteString(s)
+}
+
+func validateSectionName(name string) error {
+	switch {
+	case len(name) == 0:
+		return errors.New("empty section name")
+	case strings.ContainsAny(name, "\r\n"):
+		return fmt.Errorf("section name %q contains line break", name)
+	}
+	return nil
+}
+
+// validateKeyName returns an error if the key name cannot be read back as-is.
+func validateKeyName(name, delimiters string) error {
+	switch {
+	case len(name) == 0:
+		return errors.New("empty key name")
+	case strings.ContainsAny(name, "\r\n"):
+		return fmt.Errorf("key name %q contains line break", name)
+	case strings.TrimSpace(name) != name:
+		return fmt.Errorf("key name %q has leading or trailing whitespace", name)
+	case strings.Contains(name, "`") && (strings.Contains(name, `"`) || strings.ContainsAny(name, delimiters)):
+		return fmt.Errorf("key name %q cannot be quoted", name)
+	}
+	return nil
+}
+
+// validateValue returns an error if the value cannot be read back as-is.
+func validateValue(val string) error {
+	if strings.Contains(val, "\r") {
+		return fmt.Errorf("value %q contains carriage return", val)
+	}
+	// Multiline values are surrounded by """ and end at the last one of its line.
+	if strings.Contains(val, "\n") && strings.Contains(val, `"""`) {
+		return fmt.Errorf("multiline value %q contains \"\"\"", val)
+	}
+	return nil
+}
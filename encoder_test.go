@@ -0,0 +1,123 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoder(t *testing.T) {
+	t.Run("write sections and keys", func(t *testing.T) {
+		var buf bytes.Buffer
+		e := NewEncoder(&buf, WriteOptions{
+			Indent:               "  ",
+			SpaceAroundDelimiter: true,
+			LineBreak:            "\n",
+			SectionSpacing:       1,
+		})
+		require.NoError(t, e.Key("name", "app"))
+		require.NoError(t, e.Comment("Database settings"))
+		require.NoError(t, e.InlineComment("# primary"))
+		require.NoError(t, e.Section("db"))
+		require.NoError(t, e.Key("host", "localhost"))
+		require.NoError(t, e.InlineComment("seconds"))
+		require.NoError(t, e.Key("timeout", "30"))
+		require.NoError(t, e.BooleanKey("verbose"))
+		require.NoError(t, e.RawSection("raw", "line 1\nline 2"))
+		require.NoError(t, e.Comment("end"))
+		require.NoError(t, e.Flush())
+
+		assert.Equal(t, `name = app
+
+; Database settings
+[db] # primary
+  host = localhost
+  timeout = 30 ; seconds
+  verbose
+
+[raw]
+line 1
+line 2
+; end
+`, buf.String())
+	})
+
+	t.Run("values round trip", func(t *testing.T) {
+		keys := []struct {
+			name, value string
+		}{
+			{"plain", "value"},
+			{"comment", "a # b ; c"},
+			{"spaces", "  padded  "},
+			{"multiline", "line 1\nline 2"},
+			{"backtick", "a `b` c"},
+			{"quote", `say "hi"`},
+			{"a=b", "delimiter"},
+			{`a"b`, "quote"},
+			{"a`b", "backtick"},
+			{"#hash", "comment"},
+			{"[bracket", "section"},
+			{"empty", ""},
+		}
+
+		var buf bytes.Buffer
+		e := NewEncoder(&buf, WriteOptions{LineBreak: "\n"})
+		require.NoError(t, e.Section("keys"))
+		for _, k := range keys {
+			require.NoError(t, e.Key(k.name, k.value))
+		}
+		require.NoError(t, e.Flush())
+
+		f, err := Load(buf.Bytes())
+		require.NoError(t, err)
+		sec := f.Section("keys")
+		assert.Equal(t, len(keys), len(sec.Keys()))
+		for _, k := range keys {
+			assert.Equal(t, k.value, sec.Key(k.name).String(), "key "+k.name)
+		}
+	})
+
+	t.Run("quote always", func(t *testing.T) {
+		var buf bytes.Buffer
+		e := NewEncoder(&buf, WriteOptions{LineBreak: "\n", Quote: QuoteAlways, KeyValueDelimiter: ":"})
+		require.NoError(t, e.Key("a", "1"))
+		require.NoError(t, e.Key("b", `"x"`))
+		require.NoError(t, e.Flush())
+		assert.Equal(t, "a:\"1\"\nb:`\"x\"`\n", buf.String())
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		e := NewEncoder(&bytes.Buffer{}, WriteOptions{})
+		assert.Error(t, e.Section(""))
+		assert.Error(t, e.Section("a\nb"))
+		assert.Error(t, e.Key("", "value"))
+		assert.Error(t, e.Key("a\nb", "value"))
+		assert.Error(t, e.Key(" name", "value"))
+		assert.Error(t, e.Key("a`b=c", "value"))
+		assert.Error(t, e.Key("name", "a\r\nb"))
+		assert.Error(t, e.Key("name", "a\n\"\"\"b"))
+		assert.Error(t, e.BooleanKey("a=b"))
+		assert.Error(t, e.InlineComment("a\nb"))
+		assert.Error(t, e.RawSection("raw", "line\n[section]"))
+
+		// Invalid input does not break the encoder
+		assert.NoError(t, e.Key("name", "value"))
+		assert.NoError(t, e.Flush())
+	})
+}
@@ -16,6 +16,7 @@ package ini
 
 import (
 	"fmt"
+	"strings"
 )
 
 // ErrDelimiterNotFound indicates the error type of no delimiter is found which there should be one.
@@ -47,3 +48,106 @@ func IsErrEmptyKeyName(err error) bool {
 func (err ErrEmptyKeyName) Error() string {
 	return fmt.Sprintf("empty key name: %s", err.Line)
 }
+
+// ErrConflict indicates the error type of saving to a file that has been changed since loaded.
+type ErrConflict struct {
+	Filename string
+}
+
+// IsErrConflict returns true if the given error is an instance of ErrConflict.
+func IsErrConflict(err error) bool {
+	_, ok := err.(ErrConflict)
+	return ok
+}
+
+func (err ErrConflict) Error() string {
+	return fmt.Sprintf("file %q has been changed since loaded", err.Filename)
+}
+
+// ErrUnresolvedReference indicates the error type of a reference in value that cannot be resolved.
+type ErrUnresolvedReference struct {
+	Key       string
+	Reference string
+}
+
+// IsErrUnresolvedReference returns true if the given error is an instance of ErrUnresolvedReference.
+func IsErrUnresolvedReference(err error) bool {
+	_, ok := err.(ErrUnresolvedReference)
+	return ok
+}
+
+func (err ErrUnresolvedReference) Error() string {
+	return fmt.Sprintf("unresolved reference %q in key %q", err.Reference, err.Key)
+}
+
+// ErrResolveReference indicates the error type of a resolver failed to resolve a reference in value.
+type ErrResolveReference struct {
+	Key       string
+	Reference string
+	Err       error
+}
+
+// IsErrResolveReference returns true if the given error is an instance of ErrResolveReference.
+func IsErrResolveReference(err error) bool {
+	_, ok := err.(ErrResolveReference)
+	return ok
+}
+
+func (err ErrResolveReference) Error() string {
+	return fmt.Sprintf("resolve reference %q in key %q: %v", err.Reference, err.Key, err.Err)
+}
+
+// Unwrap returns the error returned by the resolver.
+func (err ErrResolveReference) Unwrap() error {
+	return err.Err
+}
+
+// ErrReferenceCycle indicates the error type of references in values forming a cycle.
+type ErrReferenceCycle struct {
+	// Cycle is the list of keys in the form of "section:key", the first and last are the same.
+	Cycle []string
+}
+
+// IsErrReferenceCycle returns true if the given error is an instance of ErrReferenceCycle.
+func IsErrReferenceCycle(err error) bool {
+	_, ok := err.(ErrReferenceCycle)
+	return ok
+}
+
+func (err ErrReferenceCycle) Error() string {
+	return fmt.Sprintf("reference cycle: %s", strings.Join(err.Cycle, " -> "))
+}
+
+// ErrInheritanceCycle indicates the error type of sections inheriting from each other in a cycle.
+type ErrInheritanceCycle struct {
+	// Cycle is the list of section names, the first and last are the same.
+	Cycle []string
+}
+
+// IsErrInheritanceCycle returns true if the given error is an instance of ErrInheritanceCycle.
+func IsErrInheritanceCycle(err error) bool {
+	_, ok := err.(ErrInheritanceCycle)
+	return ok
+}
+
+func (err ErrInheritanceCycle) Error() string {
+	return fmt.Sprintf("inheritance cycle: %s", strings.Join(err.Cycle, " -> "))
+}
+
+// ErrCaseConflict indicates the error type of different spellings of the same name found with PreserveCase.
+type ErrCaseConflict struct {
+	// Name is the spelling defined first.
+	Name string
+	// Conflict is the different spelling found later.
+	Conflict string
+}
+
+// IsErrCaseConflict returns true if the given error is an instance of ErrCaseConflict.
+func IsErrCaseConflict(err error) bool {
+	_, ok := err.(ErrCaseConflict)
+	return ok
+}
+
+func (err ErrCaseConflict) Error() string {
+	return fmt.Sprintf("conflicting spellings of the same name: %q and %q", err.Name, err.Conflict)
+}
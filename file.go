@@ -19,10 +19,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"unicode"
 )
 
 // File represents a combination of one or more INI files in memory.
@@ -30,7 +31,11 @@ type File struct {
 	options     LoadOptions
 	dataSources []dataSource
 
-	// Should make things safe, but sometimes doesn't matter.
+	// BlockMode makes all methods of the file, its sections and keys safe for
+	// concurrent use, which can be turned off for better performance when the
+	// file is only accessed by one goroutine at a time. Use Update to make
+	// multiple changes atomically. Exported fields such as Comment and the
+	// mappers are not guarded and must not be changed concurrently.
 	BlockMode bool
 	lock      sync.RWMutex
 
@@ -43,6 +48,23 @@ type File struct {
 	// Actual data is stored here.
 	sections map[string][]*Section
 
+	// Keyring for decrypting secret values.
+	keyring     *Keyring
+	keyringLock sync.RWMutex
+
+	// Values returned by resolvers, keyed by "scheme:argument".
+	resolverCache     map[string]string
+	resolverCacheLock sync.RWMutex
+
+	// The most recently published *Snapshot, and the generation of the latest
+	// snapshot created.
+	snapshot           atomic.Value
+	snapshotLock       sync.Mutex
+	snapshotGeneration uint64
+
+	// FooterComment is the comment after all sections and keys.
+	FooterComment string
+
 	NameMapper
 	ValueMapper
 }
@@ -58,6 +80,12 @@ func newFile(dataSources []dataSource, opts LoadOptions) *File {
 	if len(opts.ChildSectionDelimiter) == 0 {
 		opts.ChildSectionDelimiter = "."
 	}
+	if len(opts.ProfileSectionFormat) == 0 {
+		opts.ProfileSectionFormat = "{name}:{profile}"
+	}
+	if len(opts.SectionInheritanceKey) == 0 {
+		opts.SectionInheritanceKey = "@inherit"
+	}
 
 	return &File{
 		BlockMode:   true,
@@ -85,17 +113,22 @@ func (f *File) NewSection(name string) (*Section, error) {
 		return nil, errors.New("empty section name")
 	}
 
-	if (f.options.Insensitive || f.options.InsensitiveSections) && name != DefaultSection {
-		name = strings.ToLower(name)
-	}
-
 	if f.BlockMode {
 		f.lock.Lock()
 		defer f.lock.Unlock()
 	}
+	return f.newSection(name), nil
+}
+
+// newSection creates a new section, or returns the existing one unless non-unique
+// sections are allowed. The caller must hold the lock.
+func (f *File) newSection(name string) *Section {
+	spelling := name
+	name = f.sectionName(name)
 
-	if !f.options.AllowNonUniqueSections && inSlice(name, f.sectionList) {
-		return f.sections[name][0], nil
+	// The sections map indexes all names of the section list.
+	if secs := f.sections[name]; !f.options.AllowNonUniqueSections && len(secs) > 0 {
+		return secs[0]
 	}
 
 	f.sectionList = append(f.sectionList, name)
@@ -105,18 +138,23 @@ func (f *File) NewSection(name string) (*Section, error) {
 	f.sectionIndexes = append(f.sectionIndexes, len(f.sections[name]))
 
 	sec := newSection(f, name)
+	sec.spelling = spelling
 	f.sections[name] = append(f.sections[name], sec)
-
-	return sec, nil
+	return sec
 }
 
 // NewRawSection creates a new section with an unparseable body.
 func (f *File) NewRawSection(name, body string) (*Section, error) {
-	section, err := f.NewSection(name)
-	if err != nil {
-		return nil, err
+	if len(name) == 0 {
+		return nil, errors.New("empty section name")
+	}
+
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
 	}
 
+	section := f.newSection(name)
 	section.isRawSection = true
 	section.rawBody = body
 	return section, nil
@@ -150,50 +188,59 @@ func (f *File) HasSection(name string) bool {
 
 // SectionsByName returns all sections with given name.
 func (f *File) SectionsByName(name string) ([]*Section, error) {
-	if len(name) == 0 {
-		name = DefaultSection
-	}
-	if f.options.Insensitive || f.options.InsensitiveSections {
-		name = strings.ToLower(name)
-	}
-
 	if f.BlockMode {
 		f.lock.RLock()
 		defer f.lock.RUnlock()
 	}
 
-	secs := f.sections[name]
+	secs := f.sectionsByName(name)
 	if len(secs) == 0 {
-		return nil, fmt.Errorf("section %q does not exist", name)
+		return nil, fmt.Errorf("section %q does not exist", f.sectionName(name))
 	}
-
+	secs = append([]*Section(nil), secs...)
 	return secs, nil
 }
 
+// sectionName returns the name of section stored in the file for given name.
+func (f *File) sectionName(name string) string {
+	if len(name) == 0 {
+		name = DefaultSection
+	}
+	if f.options.Insensitive || f.options.InsensitiveSections {
+		name = foldCase(name, f.options.UnicodeCaseFolding)
+	}
+	return name
+}
+
+// sectionsByName returns all sections with given name. The caller must hold the lock.
+func (f *File) sectionsByName(name string) []*Section {
+	return f.sections[f.sectionName(name)]
+}
+
 // Section assumes named section exists and returns a zero-value when not.
 func (f *File) Section(name string) *Section {
-	sec, err := f.GetSection(name)
-	if err != nil {
-		if name == "" {
-			name = DefaultSection
-		}
-		sec, _ = f.NewSection(name)
-		return sec
-	}
-	return sec
+	return f.SectionWithIndex(name, 0)
 }
 
 // SectionWithIndex assumes named section exists and returns a new section when not.
 func (f *File) SectionWithIndex(name string, index int) *Section {
-	secs, err := f.SectionsByName(name)
-	if err != nil || len(secs) <= index {
-		// NOTE: It's OK here because the only possible error is empty section name,
-		// but if it's empty, this piece of code won't be executed.
-		newSec, _ := f.NewSection(name)
-		return newSec
+	secs, _ := f.SectionsByName(name)
+	if len(secs) > index {
+		return secs[index]
 	}
 
-	return secs[index]
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+	// Check again in case it was created by others meanwhile.
+	if secs = f.sectionsByName(name); len(secs) > index {
+		return secs[index]
+	}
+	if name == "" {
+		name = DefaultSection
+	}
+	return f.newSection(name)
 }
 
 // Sections returns a list of Section stored in the current instance.
@@ -203,6 +250,11 @@ func (f *File) Sections() []*Section {
 		defer f.lock.RUnlock()
 	}
 
+	return f.sectionsInOrder()
+}
+
+// sectionsInOrder returns all sections in order. The caller must hold the lock.
+func (f *File) sectionsInOrder() []*Section {
 	sections := make([]*Section, len(f.sectionList))
 	for i, name := range f.sectionList {
 		sections[i] = f.sections[name][f.sectionIndexes[i]]
@@ -210,6 +262,38 @@ func (f *File) Sections() []*Section {
 	return sections
 }
 
+// setSections replaces all sections with given list in order, and rebuilds
+// indexes of sections with the same name. The caller must hold the lock.
+func (f *File) setSections(list []*Section) {
+	f.sectionList = make([]string, len(list))
+	f.sectionIndexes = make([]int, len(list))
+	f.sections = make(map[string][]*Section, len(f.sections))
+	for i, sec := range list {
+		f.sectionList[i] = sec.name
+		f.sectionIndexes[i] = len(f.sections[sec.name])
+		f.sections[sec.name] = append(f.sections[sec.name], sec)
+	}
+}
+
+// indexOfSection returns the index of the first section with given name in the
+// list, or -1 if not found.
+func indexOfSection(list []*Section, name string) int {
+	for i, sec := range list {
+		if sec.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertSection inserts the section into the list at given index.
+func insertSection(list []*Section, index int, sec *Section) []*Section {
+	list = append(list, nil)
+	copy(list[index+1:], list[index:])
+	list[index] = sec
+	return list
+}
+
 // ChildSections returns a list of child sections of given section name.
 func (f *File) ChildSections(name string) []*Section {
 	return f.Section(name).ChildSections()
@@ -217,23 +301,33 @@ func (f *File) ChildSections(name string) []*Section {
 
 // SectionStrings returns list of section names.
 func (f *File) SectionStrings() []string {
+	if f.BlockMode {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
+	}
+
 	list := make([]string, len(f.sectionList))
-	copy(list, f.sectionList)
+	for i, sec := range f.sectionsInOrder() {
+		list[i] = sec.displayName()
+	}
 	return list
 }
 
 // DeleteSection deletes a section or all sections with given name.
 func (f *File) DeleteSection(name string) {
-	secs, err := f.SectionsByName(name)
-	if err != nil {
-		return
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
 	}
+	f.deleteSection(name)
+}
 
-	for i := 0; i < len(secs); i++ {
+// deleteSection deletes all sections with given name. The caller must hold the lock.
+func (f *File) deleteSection(name string) {
+	for i, n := 0, len(f.sectionsByName(name)); i < n; i++ {
 		// For non-unique sections, it is always needed to remove the first one so
 		// in the next iteration, the subsequent section continue having index 0.
-		// Ignoring the error as index 0 never returns an error.
-		_ = f.DeleteSectionWithIndex(name, 0)
+		f.deleteSectionWithIndex(name, 0)
 	}
 }
 
@@ -243,17 +337,17 @@ func (f *File) DeleteSectionWithIndex(name string, index int) error {
 		return fmt.Errorf("delete section with non-zero index is only allowed when non-unique sections is enabled")
 	}
 
-	if len(name) == 0 {
-		name = DefaultSection
-	}
-	if f.options.Insensitive || f.options.InsensitiveSections {
-		name = strings.ToLower(name)
-	}
-
 	if f.BlockMode {
 		f.lock.Lock()
 		defer f.lock.Unlock()
 	}
+	f.deleteSectionWithIndex(name, index)
+	return nil
+}
+
+// deleteSectionWithIndex deletes a section with given name and index. The caller must hold the lock.
+func (f *File) deleteSectionWithIndex(name string, index int) {
+	name = f.sectionName(name)
 
 	// Count occurrences of the sections
 	occurrences := 0
@@ -284,10 +378,175 @@ func (f *File) DeleteSectionWithIndex(name string, index int) error {
 
 		occurrences++
 	}
+}
+
+// Placement determines where an item is put relative to an anchor.
+type Placement int
+
+const (
+	// PlaceBefore puts the item right before the anchor.
+	PlaceBefore Placement = iota
+	// PlaceAfter puts the item right after the anchor.
+	PlaceAfter
+)
+
+// RenameSection renames all sections with given name, keeping their positions,
+// comments and keys. Child sections are not renamed.
+func (f *File) RenameSection(oldName, newName string) error {
+	return f.renameSection(oldName, newName, false)
+}
+
+// RenameSectionWithChildren is like RenameSection but also renames child sections,
+// e.g. "[parent.child]" becomes "[newparent.child]".
+func (f *File) RenameSectionWithChildren(oldName, newName string) error {
+	return f.renameSection(oldName, newName, true)
+}
+
+func (f *File) renameSection(oldName, newName string, withChildren bool) error {
+	if len(newName) == 0 {
+		return errors.New("empty section name")
+	}
+
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+
+	spelling := newName
+	oldName, newName = f.sectionName(oldName), f.sectionName(newName)
+	if len(f.sections[oldName]) == 0 {
+		return fmt.Errorf("section %q does not exist", oldName)
+	} else if oldName == f.sectionName(DefaultSection) {
+		return errors.New("cannot rename the default section")
+	} else if oldName == newName {
+		// Only the spelling may change.
+		for _, sec := range f.sections[oldName] {
+			sec.spelling = spelling
+		}
+		return nil
+	}
+
+	// Determine new names of all affected sections before changing any of them.
+	prefix := oldName + f.options.ChildSectionDelimiter
+	renames := map[string]string{oldName: newName}
+	if withChildren {
+		for _, name := range f.sectionList {
+			if strings.HasPrefix(name, prefix) {
+				renames[name] = newName + name[len(oldName):]
+			}
+		}
+	}
+	if !f.options.AllowNonUniqueSections {
+		for _, to := range renames {
+			if _, renamed := renames[to]; !renamed && len(f.sections[to]) > 0 {
+				return fmt.Errorf("section %q already exists", to)
+			}
+		}
+	}
+
+	// Child sections keep the spellings of their own parts.
+	levels := strings.Count(oldName, f.options.ChildSectionDelimiter) + 1
+	list := f.sectionsInOrder()
+	for _, sec := range list {
+		if to, ok := renames[sec.name]; ok {
+			if sec.name == oldName {
+				sec.spelling = spelling
+			} else {
+				parts := strings.SplitN(sec.displayName(), f.options.ChildSectionDelimiter, levels+1)
+				sec.spelling = spelling + f.options.ChildSectionDelimiter + parts[len(parts)-1]
+			}
+			for _, key := range sec.keys {
+				key.pinSecret()
+			}
+			sec.name = to
+			for _, key := range sec.keys {
+				for _, k := range append([]*Key{key}, key.shadows...) {
+					k.rebindSecret()
+				}
+			}
+		}
+	}
+	f.setSections(list)
+	return nil
+}
+
+// MoveSection moves the first section with given name to be right before or
+// after the first section with the anchor name.
+func (f *File) MoveSection(name string, place Placement, anchor string) error {
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+
+	name, anchor = f.sectionName(name), f.sectionName(anchor)
+	list := f.sectionsInOrder()
+	i := indexOfSection(list, name)
+	if i == -1 {
+		return fmt.Errorf("section %q does not exist", name)
+	} else if name == anchor {
+		return fmt.Errorf("cannot move section %q relative to itself", name)
+	}
+	sec := list[i]
+	list = append(list[:i], list[i+1:]...)
 
+	j := indexOfSection(list, anchor)
+	if j == -1 {
+		return fmt.Errorf("anchor section %q does not exist", anchor)
+	}
+	if place == PlaceAfter {
+		j++
+	}
+	f.setSections(insertSection(list, j, sec))
 	return nil
 }
 
+// InsertSectionAt creates a new section at given position of the section list,
+// where 0 is the first position and the number of sections is the last one.
+// It returns an error if the section already exists unless non-unique sections are allowed.
+func (f *File) InsertSectionAt(name string, index int) (*Section, error) {
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+	return f.insertSectionAt(name, index)
+}
+
+// insertSectionAt is like InsertSectionAt. The caller must hold the lock.
+func (f *File) insertSectionAt(name string, index int) (*Section, error) {
+	if len(name) == 0 {
+		return nil, errors.New("empty section name")
+	}
+	spelling := name
+	name = f.sectionName(name)
+
+	if !f.options.AllowNonUniqueSections && len(f.sections[name]) > 0 {
+		return nil, fmt.Errorf("section %q already exists", name)
+	} else if index < 0 || index > len(f.sectionList) {
+		return nil, fmt.Errorf("index %d out of range [0, %d]", index, len(f.sectionList))
+	}
+
+	sec := newSection(f, name)
+	sec.spelling = spelling
+	f.setSections(insertSection(f.sectionsInOrder(), index, sec))
+	return sec, nil
+}
+
+// NewSectionAfter creates a new section right after the first section with the
+// anchor name. It returns an error if the section already exists unless
+// non-unique sections are allowed.
+func (f *File) NewSectionAfter(anchor, name string) (*Section, error) {
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+
+	index := indexOfSection(f.sectionsInOrder(), f.sectionName(anchor))
+	if index == -1 {
+		return nil, fmt.Errorf("anchor section %q does not exist", f.sectionName(anchor))
+	}
+	return f.insertSectionAt(name, index+1)
+}
+
 func (f *File) reload(s dataSource) error {
 	r, err := s.ReadCloser()
 	if err != nil {
@@ -298,10 +557,41 @@ func (f *File) reload(s dataSource) error {
 	return f.parse(r)
 }
 
-// Reload reloads and parses all data sources.
+// Reload reloads and parses all data sources. Other goroutines do not observe
+// a partially parsed data source when BlockMode is on.
 func (f *File) Reload() (err error) {
+	f.ClearResolverCache()
+	if err = f.reloadAll(); err != nil {
+		return err
+	}
+
+	if f.options.SectionInheritance {
+		if err = f.validateInheritance(); err != nil {
+			return err
+		}
+	}
+	if f.options.ExtendedInterpolation && f.options.StrictInterpolation {
+		if err = f.validateInterpolation(); err != nil {
+			return err
+		}
+	}
+
+	// Replace the snapshot for readers once it is in use.
+	if f.snapshot.Load() != nil {
+		f.PublishSnapshot()
+	}
+	return nil
+}
+
+// reloadAll parses all data sources while holding the lock.
+func (f *File) reloadAll() error {
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+
 	for _, s := range f.dataSources {
-		if err = f.reload(s); err != nil {
+		if err := f.reload(s); err != nil {
 			// In loose mode, we create an empty default section for nonexistent files.
 			if os.IsNotExist(err) && f.options.Loose {
 				_ = f.parse(bytes.NewBuffer(nil))
@@ -310,7 +600,7 @@ func (f *File) Reload() (err error) {
 			return err
 		}
 		if f.options.ShortCircuit {
-			return nil
+			break
 		}
 	}
 	return nil
@@ -318,56 +608,186 @@ func (f *File) Reload() (err error) {
 
 // Append appends one or more data sources and reloads automatically.
 func (f *File) Append(source interface{}, others ...interface{}) error {
-	ds, err := parseDataSource(source)
-	if err != nil {
-		return err
-	}
-	f.dataSources = append(f.dataSources, ds)
-	for _, s := range others {
-		ds, err = parseDataSource(s)
+	sources := make([]dataSource, 0, 1+len(others))
+	for _, s := range append([]interface{}{source}, others...) {
+		ds, err := parseDataSource(s)
 		if err != nil {
 			return err
 		}
-		f.dataSources = append(f.dataSources, ds)
+		sources = append(sources, ds)
+	}
+
+	if f.BlockMode {
+		f.lock.Lock()
+	}
+	f.dataSources = append(f.dataSources, sources...)
+	if f.BlockMode {
+		f.lock.Unlock()
 	}
 	return f.Reload()
 }
 
+// QuotePolicy determines when values are quoted on write.
+type QuotePolicy int
+
+const (
+	// QuoteMinimal quotes values only when necessary to read back the same value.
+	QuoteMinimal QuotePolicy = iota
+	// QuoteAlways quotes all non-empty values.
+	QuoteAlways
+)
+
+// WriteOptions contains all customized options used for writing files.
+// Use DefaultWriteOptions to start with the options set by package-level variables.
+type WriteOptions struct {
+	// Indent is put before keys and comments of non-default sections.
+	Indent string
+	// AlignEquals indicates whether to align key-value delimiters with spaces within each section.
+	AlignEquals bool
+	// SpaceAroundDelimiter indicates whether to put a space on each side of key-value delimiters,
+	// otherwise DelimiterLeft and DelimiterRight are used.
+	SpaceAroundDelimiter bool
+	// DelimiterLeft and DelimiterRight are put around key-value delimiters when neither
+	// AlignEquals nor SpaceAroundDelimiter is set.
+	DelimiterLeft  string
+	DelimiterRight string
+	// KeyValueDelimiter is the delimiter between keys and values.
+	// By default, it is LoadOptions.KeyValueDelimiterOnWrite.
+	KeyValueDelimiter string
+	// CommentPrefix is put before comment lines not starting with "#" or ";". By default, it is "; ".
+	CommentPrefix string
+	// LineBreak is the line ending. By default, it is the package-level LineBreak.
+	LineBreak string
+	// SectionSpacing is the number of blank lines between sections.
+	SectionSpacing int
+	// Quote determines when values are quoted.
+	Quote QuotePolicy
+	// DefaultHeader indicates whether to write the header of the default section.
+	DefaultHeader bool
+	// Canonical indicates whether to write sections and keys sorted by Sort regardless of
+	// their order in the file, and to normalize whitespace of comments. Values are always
+	// quoted by Quote, independent of how they were quoted when loaded.
+	Canonical bool
+	// Sort is the comparators of sections and keys used in canonical mode.
+	Sort SortOptions
+}
+
+// DefaultWriteOptions returns write options set by current package-level variables,
+// i.e. PrettyFormat, PrettyEqual, PrettySection, DefaultHeader, LineBreak,
+// DefaultFormatLeft and DefaultFormatRight.
+func DefaultWriteOptions() WriteOptions {
+	opts := WriteOptions{
+		AlignEquals:          PrettyFormat,
+		SpaceAroundDelimiter: PrettyEqual,
+		DelimiterLeft:        DefaultFormatLeft,
+		DelimiterRight:       DefaultFormatRight,
+		LineBreak:            LineBreak,
+		DefaultHeader:        DefaultHeader,
+	}
+	if PrettySection {
+		opts.SectionSpacing = 1
+	}
+	return opts
+}
+
 func (f *File) writeToBuffer(indent string) (*bytes.Buffer, error) {
-	equalSign := DefaultFormatLeft + f.options.KeyValueDelimiterOnWrite + DefaultFormatRight
+	opts := DefaultWriteOptions()
+	opts.Indent = indent
+	return f.writeToBufferWithOptions(opts)
+}
+
+// quoteKeyName returns the key name quoted as needed to be read back.
+func quoteKeyName(kname, delimiters string) string {
+	switch {
+	case strings.Contains(kname, "\"") || strings.ContainsAny(kname, delimiters):
+		return "`" + kname + "`"
+	case strings.Contains(kname, "`"):
+		return `"""` + kname + `"""`
+	case len(kname) > 0 && strings.IndexByte("#;[", kname[0]) > -1:
+		// Otherwise it would be read as a comment or section
+		return "`" + kname + "`"
+	}
+	return kname
+}
+
+// quoteValue returns the value quoted as needed by given policy. Values containing
+// comment symbols are quoted when quoteCommentSymbols is true.
+func quoteValue(val string, policy QuotePolicy, quoteCommentSymbols bool) string {
+	// In case key value contains "\n", "`", "\"", "#" or ";"
+	switch {
+	case strings.ContainsAny(val, "\n`"):
+		return `"""` + val + `"""`
+	case quoteCommentSymbols && strings.ContainsAny(val, "#;"):
+		return "`" + val + "`"
+	case len(strings.TrimSpace(val)) != len(val):
+		return `"` + val + `"`
+	case policy == QuoteAlways && len(val) > 0:
+		if strings.Contains(val, `"`) {
+			return "`" + val + "`"
+		}
+		return `"` + val + `"`
+	}
+	return val
+}
+
+func (f *File) writeToBufferWithOptions(opts WriteOptions) (*bytes.Buffer, error) {
+	indent := opts.Indent
+	lineBreak := opts.LineBreak
+	if len(lineBreak) == 0 {
+		lineBreak = LineBreak
+	}
+	commentPrefix := opts.CommentPrefix
+	if len(commentPrefix) == 0 {
+		commentPrefix = "; "
+	}
+	delimiter := opts.KeyValueDelimiter
+	if len(delimiter) == 0 {
+		delimiter = f.options.KeyValueDelimiterOnWrite
+	}
 
-	if PrettyFormat || PrettyEqual {
-		equalSign = fmt.Sprintf(" %s ", f.options.KeyValueDelimiterOnWrite)
+	equalSign := opts.DelimiterLeft + delimiter + opts.DelimiterRight
+	if opts.AlignEquals || opts.SpaceAroundDelimiter {
+		equalSign = fmt.Sprintf(" %s ", delimiter)
+	}
+	sectionSpacing := strings.Repeat(lineBreak, opts.SectionSpacing)
+
+	if f.BlockMode {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
 	}
 
 	// Use buffer to make sure target is safe until finish encoding.
 	buf := bytes.NewBuffer(nil)
-	lastSectionIdx := len(f.sectionList) - 1
-	for i, sname := range f.sectionList {
-		sec := f.SectionWithIndex(sname, f.sectionIndexes[i])
+	sectionList, sectionIndexes := f.sectionList, f.sectionIndexes
+	if opts.Canonical {
+		sectionList, sectionIndexes = f.sortedSections(opts.Sort)
+	}
+
+	lastSectionIdx := len(sectionList) - 1
+	for i, sname := range sectionList {
+		sec := f.sections[sname][sectionIndexes[i]]
+		keyList := sec.keyList
+		if opts.Canonical {
+			keyList = sortedKeys(keyList, opts.Sort.keyLess())
+		}
+
 		if len(sec.Comment) > 0 {
 			// Support multiline comments
-			lines := strings.Split(sec.Comment, LineBreak)
-			for i := range lines {
-				if lines[i][0] != '#' && lines[i][0] != ';' {
-					lines[i] = "; " + lines[i]
-				} else {
-					lines[i] = lines[i][:1] + " " + strings.TrimSpace(lines[i][1:])
-				}
-
-				if _, err := buf.WriteString(lines[i] + LineBreak); err != nil {
+			for _, line := range commentLines(sec.Comment, commentPrefix, opts.Canonical) {
+				if _, err := buf.WriteString(line + lineBreak); err != nil {
 					return nil, err
 				}
 			}
 		}
 
-		if i > 0 || DefaultHeader || (i == 0 && strings.ToUpper(sec.name) != DefaultSection) {
-			if _, err := buf.WriteString("[" + sname + "]" + LineBreak); err != nil {
+		if i > 0 || opts.DefaultHeader || (i == 0 && strings.ToUpper(sec.name) != DefaultSection) {
+			header := "[" + sec.displayName() + "]" + inlineComment(sec.InlineComment, commentPrefix)
+			if _, err := buf.WriteString(header + lineBreak); err != nil {
 				return nil, err
 			}
 		} else {
 			// Write nothing if default section is empty
-			if len(sec.keyList) == 0 {
+			if len(keyList) == 0 {
 				continue
 			}
 		}
@@ -378,9 +798,9 @@ func (f *File) writeToBuffer(indent string) (*bytes.Buffer, error) {
 				return nil, err
 			}
 
-			if PrettySection && !isLastSection {
-				// Put a line between sections
-				if _, err := buf.WriteString(LineBreak); err != nil {
+			if !isLastSection {
+				// Put lines between sections
+				if _, err := buf.WriteString(sectionSpacing); err != nil {
 					return nil, err
 				}
 			}
@@ -391,14 +811,11 @@ func (f *File) writeToBuffer(indent string) (*bytes.Buffer, error) {
 		// longest key. Keys may be modified if they contain certain characters so
 		// we need to take that into account in our calculation.
 		alignLength := 0
-		if PrettyFormat {
-			for _, kname := range sec.keyList {
+		if opts.AlignEquals {
+			for _, kname := range keyList {
 				keyLength := len(kname)
-				// First case will surround key by ` and second by """
-				if strings.Contains(kname, "\"") || strings.ContainsAny(kname, f.options.KeyValueDelimiters) {
-					keyLength += 2
-				} else if strings.Contains(kname, "`") {
-					keyLength += 6
+				if key := sec.keys[kname]; !key.isAutoIncrement {
+					keyLength = len(quoteKeyName(key.displayName(), f.options.KeyValueDelimiters))
 				}
 
 				if keyLength > alignLength {
@@ -409,23 +826,31 @@ func (f *File) writeToBuffer(indent string) (*bytes.Buffer, error) {
 		alignSpaces := bytes.Repeat([]byte(" "), alignLength)
 
 	KeyList:
-		for _, kname := range sec.keyList {
-			key := sec.Key(kname)
-			if len(key.Comment) > 0 {
-				if len(indent) > 0 && sname != DefaultSection {
-					buf.WriteString(indent)
+		for _, kname := range keyList {
+			key := sec.keys[kname]
+			for _, k := range append([]*Key{key}, key.shadows...) {
+				if err := k.checkSecret(); err != nil {
+					return nil, err
+				}
+			}
+			comment := key.Comment
+			inline := inlineComment(key.InlineComment, commentPrefix)
+			// Inline comments would be read back as part of values.
+			if f.options.IgnoreInlineComment && len(inline) > 0 {
+				if len(comment) > 0 {
+					comment += lineBreak
 				}
+				comment += strings.TrimSpace(inline)
+				inline = ""
+			}
 
+			if len(comment) > 0 {
 				// Support multiline comments
-				lines := strings.Split(key.Comment, LineBreak)
-				for i := range lines {
-					if lines[i][0] != '#' && lines[i][0] != ';' {
-						lines[i] = "; " + strings.TrimSpace(lines[i])
-					} else {
-						lines[i] = lines[i][:1] + " " + strings.TrimSpace(lines[i][1:])
+				for _, line := range commentLines(comment, commentPrefix, opts.Canonical) {
+					if len(indent) > 0 && sname != DefaultSection {
+						buf.WriteString(indent)
 					}
-
-					if _, err := buf.WriteString(lines[i] + LineBreak); err != nil {
+					if _, err := buf.WriteString(line + lineBreak); err != nil {
 						return nil, err
 					}
 				}
@@ -435,13 +860,10 @@ func (f *File) writeToBuffer(indent string) (*bytes.Buffer, error) {
 				buf.WriteString(indent)
 			}
 
-			switch {
-			case key.isAutoIncrement:
+			if key.isAutoIncrement {
 				kname = "-"
-			case strings.Contains(kname, "\"") || strings.ContainsAny(kname, f.options.KeyValueDelimiters):
-				kname = "`" + kname + "`"
-			case strings.Contains(kname, "`"):
-				kname = `"""` + kname + `"""`
+			} else {
+				kname = quoteKeyName(key.displayName(), f.options.KeyValueDelimiters)
 			}
 
 			writeKeyValue := func(val string) (bool, error) {
@@ -450,30 +872,24 @@ func (f *File) writeToBuffer(indent string) (*bytes.Buffer, error) {
 				}
 
 				if key.isBooleanType {
-					buf.WriteString(LineBreak)
+					buf.WriteString(inline + lineBreak)
 					return true, nil
 				}
 
 				// Write out alignment spaces before "=" sign
-				if PrettyFormat {
+				if opts.AlignEquals {
 					buf.Write(alignSpaces[:alignLength-len(kname)])
 				}
 
-				// In case key value contains "\n", "`", "\"", "#" or ";"
-				if strings.ContainsAny(val, "\n`") {
-					val = `"""` + val + `"""`
-				} else if !f.options.IgnoreInlineComment && strings.ContainsAny(val, "#;") {
-					val = "`" + val + "`"
-				} else if len(strings.TrimSpace(val)) != len(val) {
-					val = `"` + val + `"`
-				}
-				if _, err := buf.WriteString(equalSign + val + LineBreak); err != nil {
+				if _, err := buf.WriteString(equalSign + quoteValue(val, opts.Quote, !f.options.IgnoreInlineComment) + inline + lineBreak); err != nil {
 					return false, err
 				}
+				// Only the first value is followed by the inline comment.
+				inline = ""
 				return false, nil
 			}
 
-			shadows := key.ValueWithShadows()
+			shadows := key.valueWithShadows()
 			if len(shadows) == 0 {
 				if _, err := writeKeyValue(""); err != nil {
 					return nil, err
@@ -490,15 +906,23 @@ func (f *File) writeToBuffer(indent string) (*bytes.Buffer, error) {
 			}
 
 			for _, val := range key.nestedValues {
-				if _, err := buf.WriteString(indent + "  " + val + LineBreak); err != nil {
+				if _, err := buf.WriteString(indent + "  " + val + lineBreak); err != nil {
 					return nil, err
 				}
 			}
 		}
 
-		if PrettySection && !isLastSection {
-			// Put a line between sections
-			if _, err := buf.WriteString(LineBreak); err != nil {
+		if !isLastSection {
+			// Put lines between sections
+			if _, err := buf.WriteString(sectionSpacing); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(f.FooterComment) > 0 {
+		for _, line := range commentLines(f.FooterComment, commentPrefix, opts.Canonical) {
+			if _, err := buf.WriteString(line + lineBreak); err != nil {
 				return nil, err
 			}
 		}
@@ -507,6 +931,41 @@ func (f *File) writeToBuffer(indent string) (*bytes.Buffer, error) {
 	return buf, nil
 }
 
+// commentLines returns lines of the comment to write. Lines without a comment
+// prefix are prefixed with given one, lines with one are kept as-is unless
+// normalize is true.
+func commentLines(comment, prefix string, normalize bool) []string {
+	// Comments may be joined by any line break, and carriage returns are
+	// trimmed below with other trailing spaces.
+	lines := strings.Split(comment, "\n")
+	for i, line := range lines {
+		line = strings.TrimRightFunc(line, unicode.IsSpace)
+		if normalize {
+			line = strings.TrimSpace(line)
+		}
+
+		switch {
+		case len(line) == 0 || (line[0] != '#' && line[0] != ';'):
+			line = prefix + strings.TrimSpace(line)
+		case normalize:
+			line = line[:1] + " " + strings.TrimSpace(line[1:])
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// inlineComment returns the inline comment to write after a value or section header.
+func inlineComment(comment, prefix string) string {
+	comment = strings.TrimSpace(comment)
+	if len(comment) == 0 {
+		return ""
+	} else if comment[0] != '#' && comment[0] != ';' {
+		comment = prefix + comment
+	}
+	return " " + comment
+}
+
 // WriteToIndent writes content into io.Writer with given indention.
 // If PrettyFormat has been set to be true,
 // it will align "=" sign with spaces under each section.
@@ -523,16 +982,23 @@ func (f *File) WriteTo(w io.Writer) (int64, error) {
 	return f.WriteToIndent(w, "")
 }
 
-// SaveToIndent writes content to file system with given value indention.
+// WriteToWithOptions writes file content into io.Writer with given write options.
+func (f *File) WriteToWithOptions(w io.Writer, opts WriteOptions) (int64, error) {
+	buf, err := f.writeToBufferWithOptions(opts)
+	if err != nil {
+		return 0, err
+	}
+	return buf.WriteTo(w)
+}
+
+// SaveToIndent writes content to file system atomically with given value indention.
+// See SaveToWithOptions for details.
 func (f *File) SaveToIndent(filename, indent string) error {
-	// Note: Because we are truncating with os.Create,
-	// 	so it's safer to save to a temporary file location and rename after done.
 	buf, err := f.writeToBuffer(indent)
 	if err != nil {
 		return err
 	}
-
-	return ioutil.WriteFile(filename, buf.Bytes(), 0666)
+	return writeFileAtomic(filename, buf.Bytes(), SaveOptions{})
 }
 
 // SaveTo writes content to file system.
@@ -17,8 +17,11 @@ package ini
 import (
 	"bytes"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"runtime"
 	"sort"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -445,9 +448,9 @@ domain      = mydomain.com
 		_, err = f.WriteTo(&buf)
 		require.NoError(t, err)
 
-		assert.Equal(t, `# 
+		assert.Equal(t, `#
 # general.domain
-# 
+#
 # Domain name of XX system.
 domain = mydomain.com
 ; Multiline
@@ -520,6 +523,183 @@ Note        -> Hello world!
 }
 
 // Inspired by https://github.com/go-ini/ini/issues/207
+func TestFile_WriteToWithOptions(t *testing.T) {
+	f, err := Load([]byte(`
+name = app
+
+[server]
+; The address to listen on
+host = localhost
+port = 8080
+# Comment with prefix
+motd = hello "world"
+
+[client]
+timeout = 30s
+`))
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	f.Section("client").Key("timeout").Comment = "In seconds"
+
+	t.Run("default options", func(t *testing.T) {
+		var expected, actual bytes.Buffer
+		_, err := f.WriteTo(&expected)
+		require.NoError(t, err)
+		_, err = f.WriteToWithOptions(&actual, DefaultWriteOptions())
+		require.NoError(t, err)
+		assert.Equal(t, expected.String(), actual.String())
+	})
+
+	t.Run("custom options", func(t *testing.T) {
+		var buf bytes.Buffer
+		_, err := f.WriteToWithOptions(&buf, WriteOptions{
+			Indent:            "\t",
+			KeyValueDelimiter: ":",
+			DelimiterRight:    " ",
+			CommentPrefix:     "# ",
+			LineBreak:         "\r\n",
+			SectionSpacing:    2,
+			Quote:             QuoteAlways,
+			DefaultHeader:     true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "[DEFAULT]\r\n"+
+			"name: \"app\"\r\n"+
+			"\r\n\r\n"+
+			"[server]\r\n"+
+			"\t; The address to listen on\r\n"+
+			"\thost: \"localhost\"\r\n"+
+			"\tport: \"8080\"\r\n"+
+			"\t# Comment with prefix\r\n"+
+			"\tmotd: `hello \"world\"`\r\n"+
+			"\r\n\r\n"+
+			"[client]\r\n"+
+			"\t# In seconds\r\n"+
+			"\ttimeout: \"30s\"\r\n", buf.String())
+
+		// Package-level variables are left untouched.
+		assert.True(t, PrettyFormat)
+		assert.Equal(t, "\n", LineBreak)
+	})
+
+	t.Run("independent of the package-level line break", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{IgnoreInlineComment: true}, []byte(`key = value`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+		f.Section("").Key("key").Comment = "Leading\ncomment"
+		f.Section("").Key("key").InlineComment = "# Inline"
+
+		defer func(lineBreak string) { LineBreak = lineBreak }(LineBreak)
+		LineBreak = "\r\n"
+
+		var buf bytes.Buffer
+		_, err = f.WriteToWithOptions(&buf, WriteOptions{LineBreak: "\n", SpaceAroundDelimiter: true})
+		require.NoError(t, err)
+		assert.Equal(t, "; Leading\n; comment\n# Inline\nkey = value\n", buf.String())
+	})
+
+	t.Run("save with options", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "ini")
+		require.NoError(t, err)
+		defer func() { _ = os.RemoveAll(dir) }()
+
+		filename := filepath.Join(dir, "app.ini")
+		require.NoError(t, f.SaveToWithOptions(filename, SaveOptions{
+			Write: &WriteOptions{SpaceAroundDelimiter: true},
+		}))
+
+		data, err := ioutil.ReadFile(filename)
+		require.NoError(t, err)
+		assert.Equal(t, `name = app
+[server]
+; The address to listen on
+host = localhost
+port = 8080
+# Comment with prefix
+motd = hello "world"
+[client]
+; In seconds
+timeout = 30s
+`, string(data))
+	})
+}
+
+func TestFile_Comments(t *testing.T) {
+	t.Run("preserve style and placement", func(t *testing.T) {
+		const input = `#Leading comment of the default section
+name = app # Trailing comment
+
+;Leading comment of the section
+[server] # Comment after the header
+host = localhost
+#  Indented text in comment
+port = 8080 ; Port to listen on
+motd = """Hello""" # After quoted value
+debug
+
+# Footer comment
+; of the file
+`
+		f, err := LoadSources(LoadOptions{AllowBooleanKeys: true}, []byte(input))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		sec := f.Section("server")
+		assert.Equal(t, ";Leading comment of the section", sec.Comment)
+		assert.Equal(t, "# Comment after the header", sec.InlineComment)
+		assert.Equal(t, "; Port to listen on", sec.Key("port").InlineComment)
+		assert.Equal(t, "#  Indented text in comment", sec.Key("port").Comment)
+		assert.Equal(t, "# After quoted value", sec.Key("motd").InlineComment)
+		assert.Equal(t, "# Trailing comment", f.Section("").Key("name").InlineComment)
+		assert.Equal(t, "# Footer comment\n; of the file", f.FooterComment)
+
+		sec.Key("debug").InlineComment = "Enables verbose logs"
+
+		var buf bytes.Buffer
+		_, err = f.WriteToWithOptions(&buf, WriteOptions{SpaceAroundDelimiter: true, SectionSpacing: 1})
+		require.NoError(t, err)
+		assert.Equal(t, `#Leading comment of the default section
+name = app # Trailing comment
+
+;Leading comment of the section
+[server] # Comment after the header
+host = localhost
+#  Indented text in comment
+port = 8080 ; Port to listen on
+motd = Hello # After quoted value
+debug ; Enables verbose logs
+# Footer comment
+; of the file
+`, buf.String())
+	})
+
+	t.Run("inline comments are not written after values when ignored", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{IgnoreInlineComment: true}, []byte(`key = value # part of value`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		key := f.Section("").Key("key")
+		assert.Equal(t, "value # part of value", key.String())
+		key.Comment = "# Leading"
+		key.InlineComment = "# Inline"
+
+		var buf bytes.Buffer
+		_, err = f.WriteToWithOptions(&buf, WriteOptions{SpaceAroundDelimiter: true})
+		require.NoError(t, err)
+		assert.Equal(t, "# Leading\n# Inline\nkey = value # part of value\n", buf.String())
+
+		// The inline comment becomes part of the leading comment after a round trip.
+		f, err = LoadSources(LoadOptions{IgnoreInlineComment: true}, buf.Bytes())
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		key = f.Section("").Key("key")
+		assert.Equal(t, "value # part of value", key.String())
+		assert.Equal(t, "# Leading\n# Inline", key.Comment)
+		assert.Empty(t, key.InlineComment)
+	})
+}
+
 func TestReloadAfterShadowLoad(t *testing.T) {
 	f, err := ShadowLoad([]byte(`
 [slice]
@@ -535,3 +715,145 @@ v = 3
 	require.NoError(t, f.Reload())
 	assert.Equal(t, []string{"1", "2", "3"}, f.Section("slice").Key("v").ValueWithShadows())
 }
+
+func TestFile_RenameSection(t *testing.T) {
+	data := []byte(`
+[server]
+host = localhost
+[server.tls]
+cert = a.pem
+[client]
+`)
+
+	t.Run("rename a section", func(t *testing.T) {
+		f, err := Load(data)
+		require.NoError(t, err)
+
+		require.NoError(t, f.RenameSection("server", "http"))
+		assert.Equal(t, []string{DefaultSection, "http", "server.tls", "client"}, f.SectionStrings())
+		assert.Equal(t, "localhost", f.Section("http").Key("host").String())
+		assert.Equal(t, "http", f.Section("http").Name())
+		assert.False(t, f.HasSection("server"))
+	})
+
+	t.Run("rename with children", func(t *testing.T) {
+		f, err := Load(data)
+		require.NoError(t, err)
+
+		require.NoError(t, f.RenameSectionWithChildren("server", "http"))
+		assert.Equal(t, []string{DefaultSection, "http", "http.tls", "client"}, f.SectionStrings())
+		assert.Equal(t, "a.pem", f.Section("http.tls").Key("cert").String())
+		// Keys of the renamed parent are inherited
+		assert.Equal(t, "localhost", f.Section("http.tls").Key("host").String())
+	})
+
+	t.Run("rename non-unique sections", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{AllowNonUniqueSections: true}, []byte(`
+[peer]
+ip = 1
+[interface]
+[peer]
+ip = 2
+[node]
+ip = 3
+`))
+		require.NoError(t, err)
+
+		require.NoError(t, f.RenameSection("node", "peer"))
+		secs, err := f.SectionsByName("peer")
+		require.NoError(t, err)
+		require.Len(t, secs, 3)
+		for i, sec := range secs {
+			assert.Equal(t, strconv.Itoa(i+1), sec.Key("ip").String())
+		}
+		assert.Equal(t, "3", f.SectionWithIndex("peer", 2).Key("ip").String())
+	})
+
+	t.Run("rename with insensitive sections", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{InsensitiveSections: true}, data)
+		require.NoError(t, err)
+
+		require.NoError(t, f.RenameSection("SERVER", "HTTP"))
+		assert.True(t, f.HasSection("http"))
+		assert.Equal(t, "http", f.Section("Http").Name())
+	})
+
+	t.Run("bad renames", func(t *testing.T) {
+		f, err := Load(data)
+		require.NoError(t, err)
+
+		assert.Error(t, f.RenameSection("missing", "new"))
+		assert.Error(t, f.RenameSection("server", "client"))
+		assert.Error(t, f.RenameSection("server", ""))
+		assert.Error(t, f.RenameSection(DefaultSection, "new"))
+		assert.Error(t, f.RenameSectionWithChildren("server.tls", "server"))
+	})
+}
+
+func TestFile_MoveSection(t *testing.T) {
+	f, err := LoadSources(LoadOptions{AllowNonUniqueSections: true}, []byte(`
+[b]
+k = 1
+[a]
+[b]
+k = 2
+[c]
+`))
+	require.NoError(t, err)
+
+	require.NoError(t, f.MoveSection("b", PlaceAfter, "c"))
+	assert.Equal(t, []string{DefaultSection, "a", "b", "c", "b"}, f.SectionStrings())
+	// Indexes of sections with the same name follow the new order
+	assert.Equal(t, "2", f.SectionWithIndex("b", 0).Key("k").String())
+	assert.Equal(t, "1", f.SectionWithIndex("b", 1).Key("k").String())
+
+	require.NoError(t, f.MoveSection("c", PlaceBefore, "a"))
+	assert.Equal(t, []string{DefaultSection, "c", "a", "b", "b"}, f.SectionStrings())
+
+	assert.Error(t, f.MoveSection("d", PlaceBefore, "a"))
+	assert.Error(t, f.MoveSection("a", PlaceBefore, "a"))
+	assert.Error(t, f.MoveSection("a", PlaceBefore, "d"))
+	assert.Equal(t, []string{DefaultSection, "c", "a", "b", "b"}, f.SectionStrings())
+}
+
+func TestFile_InsertSectionAt(t *testing.T) {
+	f, err := Load([]byte("[a]\n[b]"))
+	require.NoError(t, err)
+
+	sec, err := f.InsertSectionAt("c", 2)
+	require.NoError(t, err)
+	_, err = sec.NewKey("k", "v")
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultSection, "a", "c", "b"}, f.SectionStrings())
+	assert.Equal(t, "v", f.Section("c").Key("k").String())
+
+	_, err = f.InsertSectionAt("d", 4)
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultSection, "a", "c", "b", "d"}, f.SectionStrings())
+
+	_, err = f.InsertSectionAt("a", 0)
+	assert.Error(t, err)
+	_, err = f.InsertSectionAt("e", 6)
+	assert.Error(t, err)
+	_, err = f.InsertSectionAt("", 0)
+	assert.Error(t, err)
+}
+
+func TestFile_NewSectionAfter(t *testing.T) {
+	f, err := Load([]byte("[a]\n[b]"))
+	require.NoError(t, err)
+
+	sec, err := f.NewSectionAfter("a", "c")
+	require.NoError(t, err)
+	sec.Comment = "Inserted"
+	assert.Equal(t, []string{DefaultSection, "a", "c", "b"}, f.SectionStrings())
+
+	_, err = f.NewSectionAfter("b", "d")
+	require.NoError(t, err)
+	assert.Equal(t, []string{DefaultSection, "a", "c", "b", "d"}, f.SectionStrings())
+
+	_, err = f.NewSectionAfter("missing", "e")
+	assert.Error(t, err)
+	_, err = f.NewSectionAfter("a", "b")
+	assert.Error(t, err)
+}
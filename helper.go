@@ -14,6 +14,11 @@
 
 package ini
 
+import (
+	"strings"
+	"unicode"
+)
+
 func inSlice(str string, s []string) bool {
 	for _, v := range s {
 		if str == v {
@@ -22,3 +27,15 @@ func inSlice(str string, s []string) bool {
 	}
 	return false
 }
+
+// foldCase returns the name for matching case-insensitively, i.e. lowercased
+// or, if unicodeFolding is true, case-folded so that all letters of the same
+// case folding orbit are matched, e.g. "K" (Kelvin sign) and "k".
+func foldCase(name string, unicodeFolding bool) string {
+	if !unicodeFolding {
+		return strings.ToLower(name)
+	}
+	return strings.Map(func(r rune) rune {
+		return unicode.ToLower(unicode.ToUpper(r))
+	}, name)
+}
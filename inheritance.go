@@ -0,0 +1,114 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"fmt"
+	"strings"
+)
+
+// baseNames returns names of sections listed in the directive key of the
+// section. The caller must hold the lock.
+func (s *Section) baseNames() []string {
+	key := s.keys[s.keyName(s.f.options.SectionInheritanceKey)]
+	if key == nil {
+		return nil
+	}
+
+	var names []string
+	for _, name := range strings.Split(key.value, ",") {
+		if name = strings.TrimSpace(name); len(name) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// inheritedSections returns sections the section inherits from in the order of
+// precedence, i.e. depth-first in the order of the lists of directive keys.
+// Nonexistent sections and cycles are skipped. The caller must hold the lock.
+func (s *Section) inheritedSections() []*Section {
+	var list []*Section
+	seen := map[*Section]bool{s: true}
+	var walk func(sec *Section)
+	walk = func(sec *Section) {
+		for _, name := range sec.baseNames() {
+			secs := s.f.sectionsByName(name)
+			if len(secs) == 0 || seen[secs[0]] {
+				continue
+			}
+			seen[secs[0]] = true
+			list = append(list, secs[0])
+			walk(secs[0])
+		}
+	}
+	walk(s)
+	return list
+}
+
+// validateInheritance returns an error if any section inherits from
+// nonexistent sections or sections inherit from each other in a cycle.
+func (f *File) validateInheritance() error {
+	if f.BlockMode {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	states := make(map[*Section]int)
+	var path []string
+	var visit func(sec *Section) error
+	visit = func(sec *Section) error {
+		switch states[sec] {
+		case visiting:
+			start := 0
+			for i := range path {
+				if path[i] == sec.name {
+					start = i
+					break
+				}
+			}
+			cycle := append([]string(nil), path[start:]...)
+			return ErrInheritanceCycle{Cycle: append(cycle, sec.name)}
+		case visited:
+			return nil
+		}
+
+		states[sec] = visiting
+		path = append(path, sec.name)
+		for _, name := range sec.baseNames() {
+			secs := f.sectionsByName(name)
+			if len(secs) == 0 {
+				return fmt.Errorf("section %q inherits from nonexistent section %q", sec.name, name)
+			}
+			if err := visit(secs[0]); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		states[sec] = visited
+		return nil
+	}
+
+	for _, sec := range f.sectionsInOrder() {
+		if err := visit(sec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
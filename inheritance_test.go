@@ -0,0 +1,184 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func keyNames(keys []*Key) []string {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = key.Name()
+	}
+	return names
+}
+
+func TestSectionInheritance(t *testing.T) {
+	const data = `
+[root]
+timeout = 30
+retries = 3
+[base]
+@inherit = root
+host = localhost
+port = 80
+[common]
+user = admin
+port = 81
+[prod]
+@inherit = base, common
+host = example.com
+`
+
+	t.Run("inherit keys", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{SectionInheritance: true}, []byte(data))
+		require.NoError(t, err)
+
+		prod := f.Section("prod")
+		assert.Equal(t, "example.com", prod.Key("host").String())
+		// The first listed section takes precedence
+		assert.Equal(t, "80", prod.Key("port").String())
+		assert.Equal(t, "admin", prod.Key("user").String())
+		// Multi-level
+		assert.Equal(t, "30", prod.Key("timeout").String())
+		assert.True(t, prod.HasKey("retries"))
+
+		assert.Equal(t, []string{"host"}, keyNames(prod.OwnKeys()))
+		assert.Equal(t, []string{"host", "port", "timeout", "retries", "user"}, keyNames(prod.EffectiveKeys()))
+		assert.Equal(t, map[string]string{
+			"@inherit": "base, common",
+			"host":     "example.com",
+			"port":     "80",
+			"timeout":  "30",
+			"retries":  "3",
+			"user":     "admin",
+		}, prod.KeysHash())
+
+		// Directive keys are not inherited
+		_, err = prod.GetKey("@inherit")
+		assert.NoError(t, err)
+		_, err = f.Section("common").GetKey("@inherit")
+		assert.Error(t, err)
+		assert.Equal(t, "root", f.Section("base").Key("@inherit").String())
+	})
+
+	t.Run("resolve inherited values in the inheriting section", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{SectionInheritance: true, ExtendedInterpolation: true}, []byte(`
+[base]
+host = localhost
+url = http://${host}:${port}
+port = 80
+[prod]
+@inherit = base
+host = example.com
+`))
+		require.NoError(t, err)
+
+		assert.Equal(t, "http://localhost:80", f.Section("base").Key("url").String())
+		assert.Equal(t, "http://example.com:80", f.Section("prod").Key("url").String())
+
+		url, err := f.Section("prod").Key("url").Resolve()
+		require.NoError(t, err)
+		assert.Equal(t, "http://example.com:80", url)
+	})
+
+	t.Run("map to struct", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{SectionInheritance: true}, []byte(data))
+		require.NoError(t, err)
+
+		var prod struct {
+			Host    string `ini:"host"`
+			Port    int    `ini:"port"`
+			User    string `ini:"user"`
+			Timeout int    `ini:"timeout"`
+		}
+		require.NoError(t, f.Section("prod").MapTo(&prod))
+		assert.Equal(t, "example.com", prod.Host)
+		assert.Equal(t, 80, prod.Port)
+		assert.Equal(t, "admin", prod.User)
+		assert.Equal(t, 30, prod.Timeout)
+	})
+
+	t.Run("custom directive key", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{
+			Insensitive:           true,
+			SectionInheritance:    true,
+			SectionInheritanceKey: "Extends",
+		}, []byte(`
+[Base]
+Host = localhost
+[Prod]
+EXTENDS = BASE
+`))
+		require.NoError(t, err)
+		assert.Equal(t, "localhost", f.Section("prod").Key("host").String())
+		assert.Empty(t, f.Section("prod").OwnKeys())
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		f, err := Load([]byte(data))
+		require.NoError(t, err)
+		assert.False(t, f.Section("prod").HasKey("user"))
+		assert.Equal(t, []string{"@inherit", "host"}, keyNames(f.Section("prod").OwnKeys()))
+		assert.Equal(t, []string{"@inherit", "host"}, keyNames(f.Section("prod").EffectiveKeys()))
+	})
+
+	t.Run("inheritance cycle", func(t *testing.T) {
+		_, err := LoadSources(LoadOptions{SectionInheritance: true}, []byte(`
+[a]
+@inherit = b
+[b]
+@inherit = c
+[c]
+@inherit = a
+`))
+		require.Error(t, err)
+		assert.True(t, IsErrInheritanceCycle(err))
+		assert.Equal(t, "inheritance cycle: a -> b -> c -> a", err.Error())
+
+		_, err = LoadSources(LoadOptions{SectionInheritance: true}, []byte(`
+[a]
+@inherit = a
+`))
+		assert.True(t, IsErrInheritanceCycle(err))
+	})
+
+	t.Run("nonexistent section", func(t *testing.T) {
+		_, err := LoadSources(LoadOptions{SectionInheritance: true}, []byte(`
+[a]
+@inherit = missing
+`))
+		assert.Error(t, err)
+		assert.False(t, IsErrInheritanceCycle(err))
+	})
+
+	t.Run("cycle created after loading", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{SectionInheritance: true}, []byte(`
+[a]
+x = 1
+[b]
+@inherit = a
+`))
+		require.NoError(t, err)
+		f.Section("a").Key("@inherit").SetValue("b")
+		assert.Equal(t, "1", f.Section("b").Key("x").String())
+		assert.False(t, f.Section("a").HasKey("y"))
+		assert.Equal(t, []string{"x"}, keyNames(f.Section("b").EffectiveKeys()))
+	})
+}
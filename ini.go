@@ -20,6 +20,7 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 )
 
 const (
@@ -39,6 +40,9 @@ var (
 	// Variable regexp pattern: %(variable)s
 	varPattern = regexp.MustCompile(`%\(([^)]+)\)s`)
 
+	// Variables below are the defaults of writing, see DefaultWriteOptions. Prefer to pass
+	// WriteOptions to WriteToWithOptions and SaveToWithOptions rather than changing them.
+
 	// DefaultHeader explicitly writes default section header.
 	DefaultHeader = false
 
@@ -76,6 +80,8 @@ type LoadOptions struct {
 	// IgnoreContinuation indicates whether to ignore continuation lines while parsing.
 	IgnoreContinuation bool
 	// IgnoreInlineComment indicates whether to ignore comments at the end of value and treat it as part of value.
+	// Since such comments could not be read back, Key.InlineComment is written as the last line of the key's
+	// comment instead, and becomes part of Key.Comment after a round trip.
 	IgnoreInlineComment bool
 	// SkipUnrecognizableLines indicates whether to skip unrecognizable lines that do not conform to key/value pairs.
 	SkipUnrecognizableLines bool
@@ -115,6 +121,9 @@ type LoadOptions struct {
 	KeyValueDelimiterOnWrite string
 	// ChildSectionDelimiter is the delimiter that is used to separate child sections. By default, it is ".".
 	ChildSectionDelimiter string
+	// FlatSections indicates whether child sections do not fall back to keys of their parent sections,
+	// and "${section.key}" references are not split at the last "." as Python's configparser does.
+	FlatSections bool
 	// PreserveSurroundedQuote indicates whether to preserve surrounded quote (single and double quotes).
 	PreserveSurroundedQuote bool
 	// DebugFunc is called to collect debug information (currently only useful to debug parsing Python-style multiline values).
@@ -125,6 +134,70 @@ type LoadOptions struct {
 	AllowNonUniqueSections bool
 	// AllowDuplicateShadowValues indicates whether values for shadowed keys should be deduplicated.
 	AllowDuplicateShadowValues bool
+	// StrictPythonMultilineValues indicates whether Python-like multi-line values follow the rules of
+	// Python's configparser: continuation lines must be indented deeper than the key, surrounding
+	// whitespace is stripped and comment lines in between are skipped.
+	StrictPythonMultilineValues bool
+	// EmptyLinesInValues indicates whether empty lines are kept inside Python-like multi-line values
+	// rather than ending the value. It only takes effect with StrictPythonMultilineValues.
+	// Docs: https://docs.python.org/3/library/configparser.html#configparser.ConfigParser
+	EmptyLinesInValues bool
+	// ExtendedInterpolation indicates whether to substitute "${key}", "${section:key}", "${section.key}",
+	// "${scheme:argument}" and "${ref:-default}" references and unescape "$$" instead of substituting
+	// "%(key)s" references. It is a superset of Python's configparser.ExtendedInterpolation, see
+	// FlatSections to disable "${section.key}" references.
+	// References whose prefix before the first ":" is a scheme of Resolvers are resolved by the resolver
+	// unless there is a section of the same name. The default value after ":-" is used when the key or
+	// the value of the resolver does not exist or is empty. Unlike in shells, "${NAME:-default}" only
+	// looks up the key NAME, use "${env:NAME:-default}" with the "env" resolver for environment variables.
+	// Docs: https://docs.python.org/3/library/configparser.html#configparser.ExtendedInterpolation
+	ExtendedInterpolation bool
+	// StrictInterpolation indicates whether unresolvable references are errors returned by loading
+	// and Key.Resolve instead of being left as-is. It only takes effect with ExtendedInterpolation.
+	StrictInterpolation bool
+	// Resolvers is the resolvers of references by scheme used with ExtendedInterpolation. No resolvers
+	// are used by default, see EnvResolver, FileResolver and Base64Resolver for built-in ones.
+	Resolvers map[string]Resolver
+	// ResolverTimeout is the maximum duration of each call to resolvers. Zero means no timeout.
+	ResolverTimeout time.Duration
+	// DisableResolverCache indicates whether to call resolvers on every access instead of caching
+	// the values returned until File.ClearResolverCache is called.
+	DisableResolverCache bool
+	// InheritDefaultSection indicates whether keys of the default section are visible in every other
+	// section, e.g. returned by Section.Keys and Section.GetKey, unless overridden by the section.
+	// References in values of inherited keys are resolved relative to the inheriting section.
+	InheritDefaultSection bool
+	// SectionInheritance indicates whether sections inherit keys from sections listed in the value of
+	// the directive key, e.g. "@inherit = base, common", recursively. Own keys take precedence over
+	// inherited ones, which take precedence in the order of the list. References in values of
+	// inherited keys are resolved relative to the inheriting section. Loading returns an error if
+	// listed sections do not exist or form a cycle.
+	SectionInheritance bool
+	// SectionInheritanceKey is the name of the directive key used with SectionInheritance. By default,
+	// it is "@inherit".
+	SectionInheritanceKey string
+	// BooleanStates is the set of strings accepted as boolean values, matched case-insensitively
+	// against its lowercase keys. The built-in set is used when it is nil. See PythonBooleanStates,
+	// SystemdBooleanStates, MySQLBooleanStates and PHPBooleanStates for common sets.
+	BooleanStates map[string]bool
+	// StrictBooleanStates indicates whether values are matched against keys of BooleanStates exactly
+	// rather than case-insensitively, e.g. "On" is accepted but "ON" is not with {"On": true}.
+	StrictBooleanStates bool
+	// BooleanStyle is the pair of strings written for boolean values by Key.SetBool, Key.MustBool and
+	// ReflectFrom, e.g. BooleanStyleYesNo. By default, "true" and "false" are written.
+	BooleanStyle BooleanStyle
+	// PreserveCase indicates whether original spellings of section and key names are kept when they
+	// are matched case-insensitively with Insensitive, InsensitiveSections or InsensitiveKeys. Names
+	// are returned by Name, SectionStrings, KeyStrings and KeysHash, and written in the spelling they
+	// are first defined, and loading returns ErrCaseConflict when a different spelling is found.
+	PreserveCase bool
+	// UnicodeCaseFolding indicates whether case-insensitive names are matched by Unicode simple case
+	// folding rather than lowercasing, e.g. "ς" matches "Σ" and "σ".
+	UnicodeCaseFolding bool
+	// ProfileSectionFormat is the format of names of profile sections used by File.WithProfile, in which
+	// "{name}" and "{profile}" are replaced by names of the base section and the profile respectively,
+	// e.g. "{profile}.{name}". By default, it is "{name}:{profile}".
+	ProfileSectionFormat string
 }
 
 // DebugFunc is the type of function called to log parse events.
@@ -276,6 +276,95 @@ email = unknwon@local
 
 		assert.Equal(t, "unknwon", f.Section(DefaultSection).Key("user").String())
 	})
+
+	t.Run("preserve case", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{Insensitive: true, PreserveCase: true}, []byte(`
+AppName = demo
+[Database]
+Host = localhost
+[Database.Replica]
+Host = replica
+`))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{DefaultSection, "Database", "Database.Replica"}, f.SectionStrings())
+		sec := f.Section("DATABASE")
+		assert.Equal(t, "Database", sec.Name())
+		assert.Equal(t, "localhost", sec.Key("host").String())
+		assert.Equal(t, "Host", sec.Key("HOST").Name())
+		assert.Equal(t, []string{"Host"}, sec.KeyStrings())
+		assert.Equal(t, map[string]string{"Host": "localhost"}, sec.KeysHash())
+		assert.Equal(t, "replica", f.Section("database.replica").Key("host").String())
+
+		sec.Key("PORT").SetValue("5432")
+		require.NoError(t, sec.RenameKey("host", "HostName"))
+		require.NoError(t, f.RenameSectionWithChildren("database", "DB"))
+
+		var buf bytes.Buffer
+		_, err = f.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, `AppName = demo
+
+[DB]
+HostName = localhost
+PORT     = 5432
+
+[DB.Replica]
+Host = replica
+`,
+			buf.String(),
+		)
+		assert.Equal(t, "5432", f.Snapshot().Section("db").Value("port"))
+		assert.Equal(t, []string{"HostName", "PORT"}, f.Snapshot().Section("db").KeyStrings())
+	})
+
+	t.Run("case conflicts", func(t *testing.T) {
+		_, err := LoadSources(LoadOptions{Insensitive: true, PreserveCase: true}, []byte(`
+[Database]
+Host = localhost
+[database]
+Port = 5432
+`))
+		require.Error(t, err)
+		assert.True(t, IsErrCaseConflict(err))
+		assert.Equal(t, `conflicting spellings of the same name: "Database" and "database"`, err.Error())
+
+		_, err = LoadSources(LoadOptions{InsensitiveKeys: true, PreserveCase: true}, []byte(`
+[database]
+Host = localhost
+HOST = example.com
+`))
+		assert.True(t, IsErrCaseConflict(err))
+
+		// Names are different when case-sensitive.
+		f, err := LoadSources(LoadOptions{InsensitiveSections: true, PreserveCase: true}, []byte(`
+[Database]
+Host = localhost
+HOST = example.com
+`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Host", "HOST"}, f.Section("database").KeyStrings())
+	})
+
+	t.Run("unicode case folding", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{Insensitive: true, UnicodeCaseFolding: true}, []byte(`
+[ΟΔΟΣ]
+Kelvin = 1
+`))
+		require.NoError(t, err)
+		// Final sigma
+		assert.True(t, f.HasSection("οδος"))
+		// Kelvin sign
+		assert.Equal(t, "1", f.Section("οδος").Key("\u212Aelvin").String())
+
+		f, err = LoadSources(LoadOptions{Insensitive: true}, []byte(`
+[ΟΔΟΣ]
+Kelvin = 1
+`))
+		require.NoError(t, err)
+		assert.False(t, f.HasSection("οδος"))
+		assert.True(t, f.HasSection("οδοσ"))
+	})
 }
 
 func TestLoadSources(t *testing.T) {
@@ -421,9 +510,10 @@ key2=value2 #comment2`))
 				require.NotNil(t, f)
 
 				assert.Equal(t, `value`, f.Section("").Key("key1").String())
-				assert.Equal(t, `;comment`, f.Section("").Key("key1").Comment)
+				assert.Equal(t, `;comment`, f.Section("").Key("key1").InlineComment)
+				assert.Empty(t, f.Section("").Key("key1").Comment)
 				assert.Equal(t, `value2`, f.Section("").Key("key2").String())
-				assert.Equal(t, `#comment2`, f.Section("").Key("key2").Comment)
+				assert.Equal(t, `#comment2`, f.Section("").Key("key2").InlineComment)
 			})
 		})
 
@@ -471,7 +561,7 @@ key3`))
 				_, err := f.WriteTo(&buf)
 				require.NoError(t, err)
 				assert.Equal(t, `key1 = hello
-# key2
+#key2
 key3
 `,
 					buf.String(),
@@ -998,9 +1088,10 @@ key2=value2 #comment2`))
 				require.NotNil(t, f)
 
 				assert.Equal(t, `value`, f.Section("").Key("key1").String())
-				assert.Equal(t, `;comment`, f.Section("").Key("key1").Comment)
+				assert.Equal(t, `;comment`, f.Section("").Key("key1").InlineComment)
+				assert.Empty(t, f.Section("").Key("key1").Comment)
 				assert.Equal(t, `value2`, f.Section("").Key("key2").String())
-				assert.Equal(t, `#comment2`, f.Section("").Key("key2").Comment)
+				assert.Equal(t, `#comment2`, f.Section("").Key("key2").InlineComment)
 			})
 		})
 
@@ -1023,7 +1114,7 @@ key3`))
 				_, err := f.WriteTo(&buf)
 				require.NoError(t, err)
 				assert.Equal(t, `key1 = hello
-# key2
+#key2
 key3
 `,
 					buf.String(),
@@ -0,0 +1,265 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// interpolator substitutes references in values when ExtendedInterpolation is enabled.
+//
+// Supported forms are:
+//   - "${key}": the key in the same section, its parent sections or the default section.
+//   - "${section:key}" and "${section.key}": the key in the given section.
+//   - "${scheme:argument}": the value returned by the resolver of the scheme, e.g. "${env:NAME}",
+//     unless there is a section named the scheme.
+//   - "${ref:-default}": the default value when the reference is not resolved or empty.
+//   - "$$": a literal "$".
+type interpolator struct {
+	ctx    context.Context
+	strict bool
+	// Keys currently being resolved, used to detect reference cycles.
+	stack []*Key
+	// The first error occurred in non-strict mode.
+	err error
+}
+
+// keyPath returns the name of the key prefixed by its section name.
+func keyPath(k *Key) string {
+	return k.context().Name() + ":" + k.Name()
+}
+
+// cycleError returns the error naming the reference cycle ending with given key.
+func (ip *interpolator) cycleError(k *Key) error {
+	for i := range ip.stack {
+		if ip.stack[i].origin() != k.origin() || ip.stack[i].context() != k.context() {
+			continue
+		}
+
+		cycle := make([]string, 0, len(ip.stack)-i+1)
+		for _, sk := range ip.stack[i:] {
+			cycle = append(cycle, keyPath(sk))
+		}
+		return ErrReferenceCycle{Cycle: append(cycle, keyPath(k))}
+	}
+	return nil
+}
+
+// findKey returns the key referenced by name relative to the section in which
+// references of given key are resolved, or nil if there is none.
+func findKey(k *Key, name string) *Key {
+	lookup := func(section, key string) *Key {
+		sec, err := k.s.f.GetSection(section)
+		if err != nil {
+			return nil
+		}
+		nk, _ := sec.GetKey(key)
+		return nk
+	}
+
+	if i := strings.Index(name, ":"); i > -1 {
+		return lookup(name[:i], name[i+1:])
+	}
+
+	if nk, err := k.context().GetKey(name); err == nil {
+		return nk
+	}
+	if i := strings.LastIndex(name, "."); i > -1 && !k.s.f.options.FlatSections {
+		if nk := lookup(name[:i], name[i+1:]); nk != nil {
+			return nk
+		}
+	}
+	return lookup(DefaultSection, name)
+}
+
+// lookup returns the resolved value of the reference, and false if it does not exist.
+func (ip *interpolator) lookup(k *Key, ref string) (string, bool, error) {
+	if i := strings.Index(ref, ":"); i > -1 {
+		// Sections take precedence over resolvers of the same name.
+		if !k.s.f.HasSection(ref[:i]) {
+			if r := k.s.f.resolver(ref[:i]); r != nil {
+				val, err := k.s.f.resolveReference(ip.ctx, r, ref[:i], ref[i+1:])
+				if err != nil {
+					return "", false, ErrResolveReference{Key: keyPath(k), Reference: ref, Err: err}
+				}
+				return val, true, nil
+			}
+		}
+	}
+
+	nk := findKey(k, ref)
+	if nk == nil {
+		return "", false, nil
+	}
+
+	if err := ip.cycleError(nk); err != nil {
+		return "", false, err
+	}
+	if len(ip.stack) >= depthValues {
+		return "", false, fmt.Errorf("interpolation depth exceeded %d when resolving %q", depthValues, keyPath(nk))
+	}
+
+	// Referenced secret values are decrypted as Key.String does.
+	if plaintext, ok, err := nk.decryptSecret(); ok {
+		return plaintext, true, err
+	}
+
+	ip.stack = append(ip.stack, nk)
+	defer func() { ip.stack = ip.stack[:len(ip.stack)-1] }()
+	val, err := ip.expand(nk, nk.Value())
+	return val, true, err
+}
+
+// findReferenceEnd returns the index of the brace closing the reference starting
+// at the beginning of val, or -1 if there is none.
+func findReferenceEnd(val string) int {
+	depth := 0
+	for i := 0; i < len(val); i++ {
+		switch {
+		case strings.HasPrefix(val[i:], "${"):
+			depth++
+			i++
+		case val[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// expand substitutes all references in val relative to given key.
+func (ip *interpolator) expand(k *Key, val string) (string, error) {
+	if !strings.Contains(val, "$") {
+		return val, nil
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(val); i++ {
+		if val[i] != '$' || i == len(val)-1 {
+			buf.WriteByte(val[i])
+			continue
+		}
+
+		switch val[i+1] {
+		case '$':
+			buf.WriteByte('$')
+			i++
+		case '{':
+			end := findReferenceEnd(val[i:])
+			if end == -1 {
+				buf.WriteString(val[i:])
+				return buf.String(), nil
+			}
+			end += i
+
+			resolved, err := ip.reference(k, val[i+2:end])
+			if err != nil {
+				if ip.strict {
+					return "", err
+				}
+
+				// Leave the reference as-is, but remember the first error that
+				// is not simply an unresolved reference.
+				if !IsErrUnresolvedReference(err) && ip.err == nil {
+					ip.err = err
+				}
+				resolved = val[i : end+1]
+			}
+			buf.WriteString(resolved)
+			i = end
+		default:
+			buf.WriteByte(val[i])
+		}
+	}
+	return buf.String(), nil
+}
+
+// reference returns the resolved value of the reference expression inside "${}".
+// The default value of "${ref:-default}" applies to references to keys and
+// resolvers alike, environment variables are only looked up by resolvers.
+func (ip *interpolator) reference(k *Key, expr string) (string, error) {
+	ref, def := expr, ""
+	hasDefault := false
+	if i := strings.Index(expr, ":-"); i > -1 {
+		ref, def = expr[:i], expr[i+2:]
+		hasDefault = true
+	}
+
+	val, ok, err := ip.lookup(k, ref)
+	if err != nil {
+		// Values that do not exist fall back to the default value, e.g. missing files.
+		if !hasDefault || !IsErrResolveReference(err) || !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	if hasDefault && (!ok || val == "") {
+		return ip.expand(k, def)
+	}
+	if !ok {
+		return "", ErrUnresolvedReference{Key: keyPath(k), Reference: ref}
+	}
+	return val, nil
+}
+
+// extendedInterpolate substitutes references in val relative to current key.
+// In non-strict mode, unresolvable references are left as-is in the returned
+// value along with the first error other than unresolved references if any.
+func (k *Key) extendedInterpolate(ctx context.Context, val string, strict bool) (string, error) {
+	ip := &interpolator{
+		ctx:    ctx,
+		strict: strict,
+		stack:  []*Key{k},
+	}
+	val, err := ip.expand(k, val)
+	if err != nil {
+		return "", err
+	}
+	return val, ip.err
+}
+
+// Resolve returns string representation of value like String, but also returns
+// the error when a reference forms a cycle, a resolver fails, a reference
+// cannot be resolved in strict interpolation mode, or failed to decrypt.
+func (k *Key) Resolve() (string, error) {
+	return k.ResolveContext(context.Background())
+}
+
+// ResolveContext is like Resolve but passes given context to resolvers.
+func (k *Key) ResolveContext(ctx context.Context) (string, error) {
+	if plaintext, ok, err := k.decryptSecret(); ok {
+		return plaintext, err
+	}
+	return k.transformValueE(ctx, k.Value())
+}
+
+// validateInterpolation resolves all keys and returns the first error.
+func (f *File) validateInterpolation() error {
+	for _, sec := range f.Sections() {
+		for _, key := range sec.Keys() {
+			for _, val := range key.withShadows() {
+				if _, err := val.Resolve(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
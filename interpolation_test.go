@@ -0,0 +1,156 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey_ExtendedInterpolation(t *testing.T) {
+	t.Run("reference forms", func(t *testing.T) {
+		require.NoError(t, os.Setenv("INI_TEST_INTERPOLATION", "from env"))
+		defer func() { _ = os.Unsetenv("INI_TEST_INTERPOLATION") }()
+
+		f, err := LoadSources(LoadOptions{
+			ExtendedInterpolation: true,
+			Resolvers:             map[string]Resolver{"env": EnvResolver},
+		}, []byte(`
+version = 1.0
+
+[server]
+host = localhost
+port = 8080
+addr = ${host}:${port}
+
+[server.tls]
+cert = ${host}.pem
+
+[client]
+url = http://${server.addr}/v${version}
+alt = http://${server:host}
+env = ${env:INI_TEST_INTERPOLATION}
+default = ${missing:-fallback}
+env_default = ${env:INI_TEST_UNDEFINED:-fallback}
+not_env = ${INI_TEST_INTERPOLATION:-key only}
+empty =
+nested = ${empty:-${server:port}}
+escape = $${host}
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "localhost:8080", f.Section("server").Key("addr").String())
+		assert.Equal(t, "localhost.pem", f.Section("server.tls").Key("cert").String())
+		assert.Equal(t, "http://localhost:8080/v1.0", f.Section("client").Key("url").String())
+		assert.Equal(t, "http://localhost", f.Section("client").Key("alt").String())
+		assert.Equal(t, "from env", f.Section("client").Key("env").String())
+		assert.Equal(t, "fallback", f.Section("client").Key("default").String())
+		assert.Equal(t, "fallback", f.Section("client").Key("env_default").String())
+		// Defaults of references without schemes only apply to keys.
+		assert.Equal(t, "key only", f.Section("client").Key("not_env").String())
+		assert.Equal(t, "8080", f.Section("client").Key("nested").String())
+		assert.Equal(t, "${host}", f.Section("client").Key("escape").String())
+	})
+
+	t.Run("unresolved references are left as-is", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{ExtendedInterpolation: true}, []byte(`
+[section]
+key = ${missing} and ${other:key} and ${env:INI_TEST_UNDEFINED}
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		key := f.Section("section").Key("key")
+		assert.Equal(t, "${missing} and ${other:key} and ${env:INI_TEST_UNDEFINED}", key.String())
+
+		val, err := key.Resolve()
+		require.NoError(t, err)
+		assert.Equal(t, "${missing} and ${other:key} and ${env:INI_TEST_UNDEFINED}", val)
+	})
+
+	t.Run("reference cycle", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{ExtendedInterpolation: true}, []byte(`
+[a]
+x = ${b:y}
+num = ${x}
+
+[b]
+y = ${a:x}
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		_, err = f.Section("a").Key("x").Resolve()
+		require.Error(t, err)
+		assert.True(t, IsErrReferenceCycle(err))
+		assert.Equal(t, []string{"a:x", "b:y", "a:x"}, err.(ErrReferenceCycle).Cycle)
+		assert.EqualError(t, err, "reference cycle: a:x -> b:y -> a:x")
+
+		_, err = f.Section("a").Key("num").Int()
+		require.Error(t, err)
+		assert.True(t, IsErrReferenceCycle(err))
+
+		t.Run("string representation does not hang", func(t *testing.T) {
+			assert.Equal(t, "${b:y}", f.Section("b").Key("y").String())
+		})
+	})
+
+	t.Run("strict interpolation", func(t *testing.T) {
+		opts := LoadOptions{ExtendedInterpolation: true, StrictInterpolation: true}
+
+		f, err := LoadSources(opts, []byte(`
+[section]
+port = 8080
+addr = localhost:${port}
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		port, err := f.Section("section").Key("port").Int()
+		require.NoError(t, err)
+		assert.Equal(t, 8080, port)
+
+		addr, err := f.Section("section").Key("addr").Resolve()
+		require.NoError(t, err)
+		assert.Equal(t, "localhost:8080", addr)
+
+		_, err = LoadSources(opts, []byte(`
+[section]
+addr = localhost:${missing}
+`))
+		require.Error(t, err)
+		assert.True(t, IsErrUnresolvedReference(err))
+		assert.Equal(t, ErrUnresolvedReference{Key: "section:addr", Reference: "missing"}, err)
+
+		_, err = LoadSources(opts, []byte(`
+[section]
+a = ${b}
+b = ${a}
+`))
+		require.Error(t, err)
+		assert.True(t, IsErrReferenceCycle(err))
+
+		t.Run("after loading", func(t *testing.T) {
+			f.Section("section").Key("timeout").SetValue("${missing}")
+			_, err := f.Section("section").Key("timeout").Duration()
+			require.Error(t, err)
+			assert.True(t, IsErrUnresolvedReference(err))
+		})
+	})
+}
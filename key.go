@@ -16,8 +16,11 @@ package ini
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -27,6 +30,7 @@ import (
 type Key struct {
 	s               *Section
 	Comment         string
+	InlineComment   string
 	name            string
 	value           string
 	isAutoIncrement bool
@@ -36,6 +40,18 @@ type Key struct {
 	shadows  []*Key
 
 	nestedValues []string
+
+	// The original spelling of name with PreserveCase.
+	spelling string
+
+	// The path of the key the encrypted value is copied from, to which the
+	// value is bound.
+	secretPath string
+
+	// The key of another section and the section inheriting it, when the key
+	// is inherited, e.g. from the default section with InheritDefaultSection.
+	base        *Key
+	inheritedBy *Section
 }
 
 // newKey simply return a key object with given values.
@@ -47,7 +63,40 @@ func newKey(s *Section, name, val string) *Key {
 	}
 }
 
+// inheritBy returns a copy of the key inherited by given section, in which
+// references in values are resolved. Changes to values of the copy are made
+// to the inherited key.
+func (k *Key) inheritBy(s *Section) *Key {
+	base := k.origin()
+	c := *base
+	c.base = base
+	c.inheritedBy = s
+	return &c
+}
+
+// origin returns the inherited key if the key is a copy of it, or the key itself.
+func (k *Key) origin() *Key {
+	if k.base != nil {
+		return k.base
+	}
+	return k
+}
+
+// context returns the section in which references in values are resolved.
+func (k *Key) context() *Section {
+	if k.inheritedBy != nil {
+		return k.inheritedBy
+	}
+	return k.s
+}
+
 func (k *Key) addShadow(val string) error {
+	if k.base != nil {
+		err := k.base.addShadow(val)
+		k.shadows = k.base.shadows
+		return err
+	}
+
 	if k.isShadow {
 		return errors.New("cannot add shadow to another shadow key")
 	} else if k.isAutoIncrement || k.isBooleanType {
@@ -67,6 +116,7 @@ func (k *Key) addShadow(val string) error {
 	}
 
 	shadow := newKey(k.s, k.name, val)
+	shadow.spelling = k.spelling
 	shadow.isShadow = true
 	k.shadows = append(k.shadows, shadow)
 	return nil
@@ -77,10 +127,21 @@ func (k *Key) AddShadow(val string) error {
 	if !k.s.f.options.AllowShadows {
 		return errors.New("shadow key is not allowed")
 	}
+
+	if k.s.f.BlockMode {
+		k.s.f.lock.Lock()
+		defer k.s.f.lock.Unlock()
+	}
 	return k.addShadow(val)
 }
 
 func (k *Key) addNestedValue(val string) error {
+	if k.base != nil {
+		err := k.base.addNestedValue(val)
+		k.nestedValues = k.base.nestedValues
+		return err
+	}
+
 	if k.isAutoIncrement || k.isBooleanType {
 		return errors.New("cannot add nested value to auto-increment or boolean key")
 	}
@@ -94,6 +155,11 @@ func (k *Key) AddNestedValue(val string) error {
 	if !k.s.f.options.AllowNestedValues {
 		return errors.New("nested value is not allowed")
 	}
+
+	if k.s.f.BlockMode {
+		k.s.f.lock.Lock()
+		defer k.s.f.lock.Unlock()
+	}
 	return k.addNestedValue(val)
 }
 
@@ -102,17 +168,48 @@ type ValueMapper func(string) string
 
 // Name returns name of key.
 func (k *Key) Name() string {
+	if k.s.f.BlockMode {
+		k.s.f.lock.RLock()
+		defer k.s.f.lock.RUnlock()
+	}
+	return k.displayName()
+}
+
+// displayName returns the original spelling of the name with PreserveCase, and
+// the name otherwise. The caller must hold the lock.
+func (k *Key) displayName() string {
+	if k.s.f.options.PreserveCase && len(k.spelling) > 0 {
+		return k.spelling
+	}
 	return k.name
 }
 
+// Section returns the section the key belongs to.
+func (k *Key) Section() *Section {
+	return k.s
+}
+
 // Value returns raw value of key for performance purpose.
 func (k *Key) Value() string {
+	if k.s.f.BlockMode {
+		k.s.f.lock.RLock()
+		defer k.s.f.lock.RUnlock()
+	}
 	return k.value
 }
 
 // ValueWithShadows returns raw values of key and its shadows if any. Shadow
 // keys with empty values are ignored from the returned list.
 func (k *Key) ValueWithShadows() []string {
+	if k.s.f.BlockMode {
+		k.s.f.lock.RLock()
+		defer k.s.f.lock.RUnlock()
+	}
+	return k.valueWithShadows()
+}
+
+// valueWithShadows is like ValueWithShadows. The caller must hold the lock.
+func (k *Key) valueWithShadows() []string {
 	if len(k.shadows) == 0 {
 		if k.value == "" {
 			return []string{}
@@ -132,9 +229,28 @@ func (k *Key) ValueWithShadows() []string {
 	return vals
 }
 
+// withShadows returns the key followed by its shadows.
+func (k *Key) withShadows() []*Key {
+	if k.s.f.BlockMode {
+		k.s.f.lock.RLock()
+		defer k.s.f.lock.RUnlock()
+	}
+	keys := append([]*Key{k}, k.shadows...)
+	if k.inheritedBy != nil {
+		for i, shadow := range k.shadows {
+			keys[i+1] = shadow.inheritBy(k.inheritedBy)
+		}
+	}
+	return keys
+}
+
 // NestedValues returns nested values stored in the key.
 // It is possible returned value is nil if no nested values stored in the key.
 func (k *Key) NestedValues() []string {
+	if k.s.f.BlockMode {
+		k.s.f.lock.RLock()
+		defer k.s.f.lock.RUnlock()
+	}
 	return k.nestedValues
 }
 
@@ -144,6 +260,11 @@ func (k *Key) transformValue(val string) string {
 		val = k.s.f.ValueMapper(val)
 	}
 
+	if k.s.f.options.ExtendedInterpolation {
+		val, _ = k.extendedInterpolate(context.Background(), val, false)
+		return val
+	}
+
 	// Fail-fast if no indicate char found for recursive value
 	if !strings.Contains(val, "%") {
 		return val
@@ -159,8 +280,8 @@ func (k *Key) transformValue(val string) string {
 
 		// Search in the same section.
 		// If not found or found the key itself, then search again in default section.
-		nk, err := k.s.GetKey(noption)
-		if err != nil || k == nk {
+		nk, err := k.context().GetKey(noption)
+		if err != nil || k.origin() == nk.origin() {
 			nk, _ = k.s.f.Section("").GetKey(noption)
 			if nk == nil {
 				// Stop when no results found in the default section,
@@ -170,14 +291,30 @@ func (k *Key) transformValue(val string) string {
 		}
 
 		// Substitute by new value and take off leading '%(' and trailing ')s'.
-		val = strings.Replace(val, vr, nk.value, -1)
+		val = strings.Replace(val, vr, nk.Value(), -1)
 	}
 	return val
 }
 
-// String returns string representation of value.
+// transformValueE is like transformValue but also returns the error of interpolation.
+func (k *Key) transformValueE(ctx context.Context, val string) (string, error) {
+	if !k.s.f.options.ExtendedInterpolation {
+		return k.transformValue(val), nil
+	}
+
+	if k.s.f.ValueMapper != nil {
+		val = k.s.f.ValueMapper(val)
+	}
+	return k.extendedInterpolate(ctx, val, k.s.f.options.StrictInterpolation)
+}
+
+// String returns string representation of value. Encrypted values are decrypted
+// when a keyring is attached to the file, and returned as-is if failed to decrypt.
 func (k *Key) String() string {
-	return k.transformValue(k.value)
+	if plaintext, ok, err := k.decryptSecret(); ok && err == nil {
+		return plaintext
+	}
+	return k.transformValue(k.Value())
 }
 
 // Validate accepts a validate function which can
@@ -198,49 +335,77 @@ func parseBool(str string) (value bool, err error) {
 	case "0", "f", "F", "false", "FALSE", "False", "NO", "no", "No", "n", "OFF", "off", "Off":
 		return false, nil
 	}
-	return false, fmt.Errorf("parsing \"%s\": invalid syntax", str)
+	return false, invalidBoolError(str)
+}
+
+func invalidBoolError(str string) error {
+	return fmt.Errorf("parsing \"%s\": invalid syntax", str)
 }
 
 // Bool returns bool type value.
 func (k *Key) Bool() (bool, error) {
-	return parseBool(k.String())
+	str, err := k.Resolve()
+	if err != nil {
+		return false, err
+	}
+	return k.s.f.parseBool(str)
 }
 
 // Float64 returns float64 type value.
 func (k *Key) Float64() (float64, error) {
-	return strconv.ParseFloat(k.String(), 64)
+	str, err := k.Resolve()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(str, 64)
 }
 
 // Int returns int type value.
 func (k *Key) Int() (int, error) {
-	v, err := strconv.ParseInt(k.String(), 0, 64)
+	v, err := k.Int64()
 	return int(v), err
 }
 
 // Int64 returns int64 type value.
 func (k *Key) Int64() (int64, error) {
-	return strconv.ParseInt(k.String(), 0, 64)
+	str, err := k.Resolve()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(str, 0, 64)
 }
 
 // Uint returns uint type valued.
 func (k *Key) Uint() (uint, error) {
-	u, e := strconv.ParseUint(k.String(), 0, 64)
+	u, e := k.Uint64()
 	return uint(u), e
 }
 
 // Uint64 returns uint64 type value.
 func (k *Key) Uint64() (uint64, error) {
-	return strconv.ParseUint(k.String(), 0, 64)
+	str, err := k.Resolve()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(str, 0, 64)
 }
 
 // Duration returns time.Duration type value.
 func (k *Key) Duration() (time.Duration, error) {
-	return time.ParseDuration(k.String())
+	str, err := k.Resolve()
+	if err != nil {
+		return 0, err
+	}
+	return time.ParseDuration(str)
 }
 
 // TimeFormat parses with given format and returns time.Time type value.
 func (k *Key) TimeFormat(format string) (time.Time, error) {
-	return time.Parse(format, k.String())
+	str, err := k.Resolve()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(format, str)
 }
 
 // Time parses with RFC3339 format and returns time.Time type value.
@@ -248,11 +413,83 @@ func (k *Key) Time() (time.Time, error) {
 	return k.TimeFormat(time.RFC3339)
 }
 
+// ByteSize returns ByteSize type value, see ParseByteSize for the format.
+func (k *Key) ByteSize() (ByteSize, error) {
+	str, err := k.Resolve()
+	if err != nil {
+		return 0, err
+	}
+	return ParseByteSize(str)
+}
+
+// Percent returns Percent type value, see ParsePercent for the format.
+func (k *Key) Percent() (Percent, error) {
+	str, err := k.Resolve()
+	if err != nil {
+		return 0, err
+	}
+	return ParsePercent(str)
+}
+
+// Rate returns Rate type value, see ParseRate for the format.
+func (k *Key) Rate() (Rate, error) {
+	str, err := k.Resolve()
+	if err != nil {
+		return Rate{}, err
+	}
+	return ParseRate(str)
+}
+
+// IP returns net.IP type value of an IPv4 or IPv6 address.
+func (k *Key) IP() (net.IP, error) {
+	str, err := k.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	return parseIP(str)
+}
+
+// IPNet returns the network of an IP address in CIDR notation, e.g. "10.0.0.0/8" for "10.1.2.3/8".
+func (k *Key) IPNet() (*net.IPNet, error) {
+	str, err := k.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	return parseIPNet(str)
+}
+
+// HostPort returns HostPort type value, see ParseHostPort for the format.
+func (k *Key) HostPort() (HostPort, error) {
+	str, err := k.Resolve()
+	if err != nil {
+		return HostPort{}, err
+	}
+	return ParseHostPort(str)
+}
+
+// URL returns *url.URL type value, it returns error if value is empty.
+func (k *Key) URL() (*url.URL, error) {
+	str, err := k.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	return parseURL(str)
+}
+
+// HardwareAddr returns net.HardwareAddr type value of a MAC address.
+func (k *Key) HardwareAddr() (net.HardwareAddr, error) {
+	str, err := k.Resolve()
+	if err != nil {
+		return nil, err
+	}
+	return parseHardwareAddr(str)
+}
+
 // MustString returns default value if key value is empty.
 func (k *Key) MustString(defaultVal string) string {
 	val := k.String()
 	if len(val) == 0 {
-		k.value = defaultVal
+		k.SetValue(defaultVal)
 		return defaultVal
 	}
 	return val
@@ -263,7 +500,7 @@ func (k *Key) MustString(defaultVal string) string {
 func (k *Key) MustBool(defaultVal ...bool) bool {
 	val, err := k.Bool()
 	if len(defaultVal) > 0 && err != nil {
-		k.value = strconv.FormatBool(defaultVal[0])
+		k.SetBool(defaultVal[0])
 		return defaultVal[0]
 	}
 	return val
@@ -274,7 +511,7 @@ func (k *Key) MustBool(defaultVal ...bool) bool {
 func (k *Key) MustFloat64(defaultVal ...float64) float64 {
 	val, err := k.Float64()
 	if len(defaultVal) > 0 && err != nil {
-		k.value = strconv.FormatFloat(defaultVal[0], 'f', -1, 64)
+		k.SetValue(strconv.FormatFloat(defaultVal[0], 'f', -1, 64))
 		return defaultVal[0]
 	}
 	return val
@@ -285,7 +522,7 @@ func (k *Key) MustFloat64(defaultVal ...float64) float64 {
 func (k *Key) MustInt(defaultVal ...int) int {
 	val, err := k.Int()
 	if len(defaultVal) > 0 && err != nil {
-		k.value = strconv.FormatInt(int64(defaultVal[0]), 10)
+		k.SetValue(strconv.FormatInt(int64(defaultVal[0]), 10))
 		return defaultVal[0]
 	}
 	return val
@@ -296,7 +533,7 @@ func (k *Key) MustInt(defaultVal ...int) int {
 func (k *Key) MustInt64(defaultVal ...int64) int64 {
 	val, err := k.Int64()
 	if len(defaultVal) > 0 && err != nil {
-		k.value = strconv.FormatInt(defaultVal[0], 10)
+		k.SetValue(strconv.FormatInt(defaultVal[0], 10))
 		return defaultVal[0]
 	}
 	return val
@@ -307,7 +544,7 @@ func (k *Key) MustInt64(defaultVal ...int64) int64 {
 func (k *Key) MustUint(defaultVal ...uint) uint {
 	val, err := k.Uint()
 	if len(defaultVal) > 0 && err != nil {
-		k.value = strconv.FormatUint(uint64(defaultVal[0]), 10)
+		k.SetValue(strconv.FormatUint(uint64(defaultVal[0]), 10))
 		return defaultVal[0]
 	}
 	return val
@@ -318,7 +555,7 @@ func (k *Key) MustUint(defaultVal ...uint) uint {
 func (k *Key) MustUint64(defaultVal ...uint64) uint64 {
 	val, err := k.Uint64()
 	if len(defaultVal) > 0 && err != nil {
-		k.value = strconv.FormatUint(defaultVal[0], 10)
+		k.SetValue(strconv.FormatUint(defaultVal[0], 10))
 		return defaultVal[0]
 	}
 	return val
@@ -329,7 +566,7 @@ func (k *Key) MustUint64(defaultVal ...uint64) uint64 {
 func (k *Key) MustDuration(defaultVal ...time.Duration) time.Duration {
 	val, err := k.Duration()
 	if len(defaultVal) > 0 && err != nil {
-		k.value = defaultVal[0].String()
+		k.SetValue(defaultVal[0].String())
 		return defaultVal[0]
 	}
 	return val
@@ -340,7 +577,7 @@ func (k *Key) MustDuration(defaultVal ...time.Duration) time.Duration {
 func (k *Key) MustTimeFormat(format string, defaultVal ...time.Time) time.Time {
 	val, err := k.TimeFormat(format)
 	if len(defaultVal) > 0 && err != nil {
-		k.value = defaultVal[0].Format(format)
+		k.SetValue(defaultVal[0].Format(format))
 		return defaultVal[0]
 	}
 	return val
@@ -352,6 +589,94 @@ func (k *Key) MustTime(defaultVal ...time.Time) time.Time {
 	return k.MustTimeFormat(time.RFC3339, defaultVal...)
 }
 
+// MustByteSize always returns value without error,
+// it returns zero value if error occurs.
+func (k *Key) MustByteSize(defaultVal ...ByteSize) ByteSize {
+	val, err := k.ByteSize()
+	if len(defaultVal) > 0 && err != nil {
+		k.SetValue(defaultVal[0].String())
+		return defaultVal[0]
+	}
+	return val
+}
+
+// MustPercent always returns value without error,
+// it returns zero value if error occurs.
+func (k *Key) MustPercent(defaultVal ...Percent) Percent {
+	val, err := k.Percent()
+	if len(defaultVal) > 0 && err != nil {
+		k.SetValue(defaultVal[0].String())
+		return defaultVal[0]
+	}
+	return val
+}
+
+// MustRate always returns value without error,
+// it returns zero value if error occurs.
+func (k *Key) MustRate(defaultVal ...Rate) Rate {
+	val, err := k.Rate()
+	if len(defaultVal) > 0 && err != nil {
+		k.SetValue(defaultVal[0].String())
+		return defaultVal[0]
+	}
+	return val
+}
+
+// MustIP always returns value without error,
+// it returns nil if error occurs.
+func (k *Key) MustIP(defaultVal ...net.IP) net.IP {
+	val, err := k.IP()
+	if len(defaultVal) > 0 && err != nil {
+		k.SetValue(defaultVal[0].String())
+		return defaultVal[0]
+	}
+	return val
+}
+
+// MustIPNet always returns value without error,
+// it returns nil if error occurs.
+func (k *Key) MustIPNet(defaultVal ...*net.IPNet) *net.IPNet {
+	val, err := k.IPNet()
+	if len(defaultVal) > 0 && err != nil {
+		k.SetValue(defaultVal[0].String())
+		return defaultVal[0]
+	}
+	return val
+}
+
+// MustHostPort always returns value without error,
+// it returns zero value if error occurs.
+func (k *Key) MustHostPort(defaultVal ...HostPort) HostPort {
+	val, err := k.HostPort()
+	if len(defaultVal) > 0 && err != nil {
+		k.SetValue(defaultVal[0].String())
+		return defaultVal[0]
+	}
+	return val
+}
+
+// MustURL always returns value without error,
+// it returns nil if error occurs.
+func (k *Key) MustURL(defaultVal ...*url.URL) *url.URL {
+	val, err := k.URL()
+	if len(defaultVal) > 0 && err != nil {
+		k.SetValue(defaultVal[0].String())
+		return defaultVal[0]
+	}
+	return val
+}
+
+// MustHardwareAddr always returns value without error,
+// it returns nil if error occurs.
+func (k *Key) MustHardwareAddr(defaultVal ...net.HardwareAddr) net.HardwareAddr {
+	val, err := k.HardwareAddr()
+	if len(defaultVal) > 0 && err != nil {
+		k.SetValue(defaultVal[0].String())
+		return defaultVal[0]
+	}
+	return val
+}
+
 // In always returns value without error,
 // it returns default value if error occurs or doesn't fit into candidates.
 func (k *Key) In(defaultVal string, candidates []string) string {
@@ -488,6 +813,36 @@ func (k *Key) RangeTime(defaultVal, min, max time.Time) time.Time {
 	return k.RangeTimeFormat(time.RFC3339, defaultVal, min, max)
 }
 
+// RangeByteSize checks if value is in given range inclusively,
+// and returns default value if it's not.
+func (k *Key) RangeByteSize(defaultVal, min, max ByteSize) ByteSize {
+	val := k.MustByteSize()
+	if val < min || val > max {
+		return defaultVal
+	}
+	return val
+}
+
+// RangePercent checks if value is in given range inclusively,
+// and returns default value if it's not.
+func (k *Key) RangePercent(defaultVal, min, max Percent) Percent {
+	val := k.MustPercent()
+	if val < min || val > max {
+		return defaultVal
+	}
+	return val
+}
+
+// RangeRate checks if value is in given range inclusively by the number of
+// events per second, and returns default value if it's not.
+func (k *Key) RangeRate(defaultVal, min, max Rate) Rate {
+	val := k.MustRate()
+	if val.PerSecond() < min.PerSecond() || val.PerSecond() > max.PerSecond() {
+		return defaultVal
+	}
+	return val
+}
+
 // Strings returns list of string divided by given delimiter.
 func (k *Key) Strings(delim string) []string {
 	str := k.String()
@@ -599,6 +954,54 @@ func (k *Key) Times(delim string) []time.Time {
 	return k.TimesFormat(time.RFC3339, delim)
 }
 
+// ByteSizes returns list of ByteSize divided by given delimiter. Any invalid input will be treated as zero value.
+func (k *Key) ByteSizes(delim string) []ByteSize {
+	vals, _ := k.parseByteSizes(k.Strings(delim), true, false)
+	return vals
+}
+
+// Percents returns list of Percent divided by given delimiter. Any invalid input will be treated as zero value.
+func (k *Key) Percents(delim string) []Percent {
+	vals, _ := k.parsePercents(k.Strings(delim), true, false)
+	return vals
+}
+
+// Rates returns list of Rate divided by given delimiter. Any invalid input will be treated as zero value.
+func (k *Key) Rates(delim string) []Rate {
+	vals, _ := k.parseRates(k.Strings(delim), true, false)
+	return vals
+}
+
+// IPs returns list of net.IP divided by given delimiter. Any invalid input will be treated as nil.
+func (k *Key) IPs(delim string) []net.IP {
+	vals, _ := k.parseIPs(k.Strings(delim), true, false)
+	return vals
+}
+
+// IPNets returns list of *net.IPNet divided by given delimiter. Any invalid input will be treated as nil.
+func (k *Key) IPNets(delim string) []*net.IPNet {
+	vals, _ := k.parseIPNets(k.Strings(delim), true, false)
+	return vals
+}
+
+// HostPorts returns list of HostPort divided by given delimiter. Any invalid input will be treated as zero value.
+func (k *Key) HostPorts(delim string) []HostPort {
+	vals, _ := k.parseHostPorts(k.Strings(delim), true, false)
+	return vals
+}
+
+// URLs returns list of *url.URL divided by given delimiter. Any invalid input will be treated as nil.
+func (k *Key) URLs(delim string) []*url.URL {
+	vals, _ := k.parseURLs(k.Strings(delim), true, false)
+	return vals
+}
+
+// HardwareAddrs returns list of net.HardwareAddr divided by given delimiter. Any invalid input will be treated as nil.
+func (k *Key) HardwareAddrs(delim string) []net.HardwareAddr {
+	vals, _ := k.parseHardwareAddrs(k.Strings(delim), true, false)
+	return vals
+}
+
 // ValidFloat64s returns list of float64 divided by given delimiter. If some value is not float, then
 // it will not be included to result list.
 func (k *Key) ValidFloat64s(delim string) []float64 {
@@ -652,6 +1055,62 @@ func (k *Key) ValidTimes(delim string) []time.Time {
 	return k.ValidTimesFormat(time.RFC3339, delim)
 }
 
+// ValidByteSizes returns list of ByteSize divided by given delimiter. If some value is not a byte size,
+// then it will not be included to result list.
+func (k *Key) ValidByteSizes(delim string) []ByteSize {
+	vals, _ := k.parseByteSizes(k.Strings(delim), false, false)
+	return vals
+}
+
+// ValidPercents returns list of Percent divided by given delimiter. If some value is not a percentage,
+// then it will not be included to result list.
+func (k *Key) ValidPercents(delim string) []Percent {
+	vals, _ := k.parsePercents(k.Strings(delim), false, false)
+	return vals
+}
+
+// ValidRates returns list of Rate divided by given delimiter. If some value is not a rate,
+// then it will not be included to result list.
+func (k *Key) ValidRates(delim string) []Rate {
+	vals, _ := k.parseRates(k.Strings(delim), false, false)
+	return vals
+}
+
+// ValidIPs returns list of net.IP divided by given delimiter. If some value is not an IP address,
+// then it will not be included to result list.
+func (k *Key) ValidIPs(delim string) []net.IP {
+	vals, _ := k.parseIPs(k.Strings(delim), false, false)
+	return vals
+}
+
+// ValidIPNets returns list of *net.IPNet divided by given delimiter. If some value is not in CIDR
+// notation, then it will not be included to result list.
+func (k *Key) ValidIPNets(delim string) []*net.IPNet {
+	vals, _ := k.parseIPNets(k.Strings(delim), false, false)
+	return vals
+}
+
+// ValidHostPorts returns list of HostPort divided by given delimiter. If some value is not a host
+// and port, then it will not be included to result list.
+func (k *Key) ValidHostPorts(delim string) []HostPort {
+	vals, _ := k.parseHostPorts(k.Strings(delim), false, false)
+	return vals
+}
+
+// ValidURLs returns list of *url.URL divided by given delimiter. If some value is not a URL,
+// then it will not be included to result list.
+func (k *Key) ValidURLs(delim string) []*url.URL {
+	vals, _ := k.parseURLs(k.Strings(delim), false, false)
+	return vals
+}
+
+// ValidHardwareAddrs returns list of net.HardwareAddr divided by given delimiter. If some value is
+// not a MAC address, then it will not be included to result list.
+func (k *Key) ValidHardwareAddrs(delim string) []net.HardwareAddr {
+	vals, _ := k.parseHardwareAddrs(k.Strings(delim), false, false)
+	return vals
+}
+
 // StrictFloat64s returns list of float64 divided by given delimiter or error on first invalid input.
 func (k *Key) StrictFloat64s(delim string) ([]float64, error) {
 	return k.parseFloat64s(k.Strings(delim), false, true)
@@ -694,11 +1153,51 @@ func (k *Key) StrictTimes(delim string) ([]time.Time, error) {
 	return k.StrictTimesFormat(time.RFC3339, delim)
 }
 
+// StrictByteSizes returns list of ByteSize divided by given delimiter or error on first invalid input.
+func (k *Key) StrictByteSizes(delim string) ([]ByteSize, error) {
+	return k.parseByteSizes(k.Strings(delim), false, true)
+}
+
+// StrictPercents returns list of Percent divided by given delimiter or error on first invalid input.
+func (k *Key) StrictPercents(delim string) ([]Percent, error) {
+	return k.parsePercents(k.Strings(delim), false, true)
+}
+
+// StrictRates returns list of Rate divided by given delimiter or error on first invalid input.
+func (k *Key) StrictRates(delim string) ([]Rate, error) {
+	return k.parseRates(k.Strings(delim), false, true)
+}
+
+// StrictIPs returns list of net.IP divided by given delimiter or error on first invalid input.
+func (k *Key) StrictIPs(delim string) ([]net.IP, error) {
+	return k.parseIPs(k.Strings(delim), false, true)
+}
+
+// StrictIPNets returns list of *net.IPNet divided by given delimiter or error on first invalid input.
+func (k *Key) StrictIPNets(delim string) ([]*net.IPNet, error) {
+	return k.parseIPNets(k.Strings(delim), false, true)
+}
+
+// StrictHostPorts returns list of HostPort divided by given delimiter or error on first invalid input.
+func (k *Key) StrictHostPorts(delim string) ([]HostPort, error) {
+	return k.parseHostPorts(k.Strings(delim), false, true)
+}
+
+// StrictURLs returns list of *url.URL divided by given delimiter or error on first invalid input.
+func (k *Key) StrictURLs(delim string) ([]*url.URL, error) {
+	return k.parseURLs(k.Strings(delim), false, true)
+}
+
+// StrictHardwareAddrs returns list of net.HardwareAddr divided by given delimiter or error on first invalid input.
+func (k *Key) StrictHardwareAddrs(delim string) ([]net.HardwareAddr, error) {
+	return k.parseHardwareAddrs(k.Strings(delim), false, true)
+}
+
 // parseBools transforms strings to bools.
 func (k *Key) parseBools(strs []string, addInvalid, returnOnInvalid bool) ([]bool, error) {
 	vals := make([]bool, 0, len(strs))
 	parser := func(str string) (interface{}, error) {
-		val, err := parseBool(str)
+		val, err := k.s.f.parseBool(str)
 		return val, err
 	}
 	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
@@ -810,6 +1309,134 @@ func (k *Key) parseTimesFormat(format string, strs []string, addInvalid, returnO
 	return vals, err
 }
 
+// parseByteSizes transforms strings to byte sizes.
+func (k *Key) parseByteSizes(strs []string, addInvalid, returnOnInvalid bool) ([]ByteSize, error) {
+	vals := make([]ByteSize, 0, len(strs))
+	parser := func(str string) (interface{}, error) {
+		val, err := ParseByteSize(str)
+		return val, err
+	}
+	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
+	if err == nil {
+		for _, val := range rawVals {
+			vals = append(vals, val.(ByteSize))
+		}
+	}
+	return vals, err
+}
+
+// parsePercents transforms strings to percentages.
+func (k *Key) parsePercents(strs []string, addInvalid, returnOnInvalid bool) ([]Percent, error) {
+	vals := make([]Percent, 0, len(strs))
+	parser := func(str string) (interface{}, error) {
+		val, err := ParsePercent(str)
+		return val, err
+	}
+	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
+	if err == nil {
+		for _, val := range rawVals {
+			vals = append(vals, val.(Percent))
+		}
+	}
+	return vals, err
+}
+
+// parseRates transforms strings to rates.
+func (k *Key) parseRates(strs []string, addInvalid, returnOnInvalid bool) ([]Rate, error) {
+	vals := make([]Rate, 0, len(strs))
+	parser := func(str string) (interface{}, error) {
+		val, err := ParseRate(str)
+		return val, err
+	}
+	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
+	if err == nil {
+		for _, val := range rawVals {
+			vals = append(vals, val.(Rate))
+		}
+	}
+	return vals, err
+}
+
+// parseIPs transforms strings to IP addresses.
+func (k *Key) parseIPs(strs []string, addInvalid, returnOnInvalid bool) ([]net.IP, error) {
+	vals := make([]net.IP, 0, len(strs))
+	parser := func(str string) (interface{}, error) {
+		val, err := parseIP(str)
+		return val, err
+	}
+	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
+	if err == nil {
+		for _, val := range rawVals {
+			vals = append(vals, val.(net.IP))
+		}
+	}
+	return vals, err
+}
+
+// parseIPNets transforms strings to IP networks.
+func (k *Key) parseIPNets(strs []string, addInvalid, returnOnInvalid bool) ([]*net.IPNet, error) {
+	vals := make([]*net.IPNet, 0, len(strs))
+	parser := func(str string) (interface{}, error) {
+		val, err := parseIPNet(str)
+		return val, err
+	}
+	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
+	if err == nil {
+		for _, val := range rawVals {
+			vals = append(vals, val.(*net.IPNet))
+		}
+	}
+	return vals, err
+}
+
+// parseHostPorts transforms strings to hosts and ports.
+func (k *Key) parseHostPorts(strs []string, addInvalid, returnOnInvalid bool) ([]HostPort, error) {
+	vals := make([]HostPort, 0, len(strs))
+	parser := func(str string) (interface{}, error) {
+		val, err := ParseHostPort(str)
+		return val, err
+	}
+	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
+	if err == nil {
+		for _, val := range rawVals {
+			vals = append(vals, val.(HostPort))
+		}
+	}
+	return vals, err
+}
+
+// parseURLs transforms strings to URLs.
+func (k *Key) parseURLs(strs []string, addInvalid, returnOnInvalid bool) ([]*url.URL, error) {
+	vals := make([]*url.URL, 0, len(strs))
+	parser := func(str string) (interface{}, error) {
+		val, err := parseURL(str)
+		return val, err
+	}
+	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
+	if err == nil {
+		for _, val := range rawVals {
+			vals = append(vals, val.(*url.URL))
+		}
+	}
+	return vals, err
+}
+
+// parseHardwareAddrs transforms strings to MAC addresses.
+func (k *Key) parseHardwareAddrs(strs []string, addInvalid, returnOnInvalid bool) ([]net.HardwareAddr, error) {
+	vals := make([]net.HardwareAddr, 0, len(strs))
+	parser := func(str string) (interface{}, error) {
+		val, err := parseHardwareAddr(str)
+		return val, err
+	}
+	rawVals, err := k.doParse(strs, addInvalid, returnOnInvalid, parser)
+	if err == nil {
+		for _, val := range rawVals {
+			vals = append(vals, val.(net.HardwareAddr))
+		}
+	}
+	return vals, err
+}
+
 // doParse transforms strings to different types
 func (k *Key) doParse(strs []string, addInvalid, returnOnInvalid bool, parser Parser) ([]interface{}, error) {
 	vals := make([]interface{}, 0, len(strs))
@@ -831,7 +1458,24 @@ func (k *Key) SetValue(v string) {
 		k.s.f.lock.Lock()
 		defer k.s.f.lock.Unlock()
 	}
+	k.setValue(v)
+}
+
+// SetBool changes key value to the boolean value written in the boolean style of the file.
+func (k *Key) SetBool(v bool) {
+	k.SetValue(k.s.f.formatBool(v))
+}
 
+// setValue changes key value. The caller must hold the lock.
+func (k *Key) setValue(v string) {
 	k.value = v
-	k.s.keysHash[k.name] = v
+	k.secretPath = ""
+	if k.base != nil {
+		k.base.setValue(v)
+		return
+	}
+	// Shadows are not tracked by the keys hash of the section.
+	if !k.isShadow {
+		k.s.keysHash[k.name] = v
+	}
 }
@@ -0,0 +1,51 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"os"
+)
+
+// FileLock is an advisory lock held on behalf of a file for coordinating
+// writers across processes, e.g. around File.Reload and File.SaveIfUnchanged.
+//
+// The lock is taken on a separate "<filename>.lock" file because saving replaces
+// the file itself. It is advisory, so only processes using LockFile respect it.
+type FileLock struct {
+	f *os.File
+}
+
+// LockFile blocks until it acquires the exclusive lock of given file.
+func LockFile(filename string) (*FileLock, error) {
+	f, err := os.OpenFile(filename+".lock", os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = lockFile(f); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &FileLock{f: f}, nil
+}
+
+// Unlock releases the lock.
+func (l *FileLock) Unlock() error {
+	if err := unlockFile(l.f); err != nil {
+		_ = l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// HostPort is a network address of a host and a port, e.g. "localhost:8080",
+// "[::1]:80" or ":8080".
+type HostPort struct {
+	Host string
+	Port int
+}
+
+// ParseHostPort parses a network address in the form of "host:port", where the
+// port is a number and IPv6 hosts are enclosed in square brackets.
+func ParseHostPort(str string) (HostPort, error) {
+	host, port, err := net.SplitHostPort(strings.TrimSpace(str))
+	if err != nil {
+		return HostPort{}, err
+	}
+	n, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return HostPort{}, fmt.Errorf("parsing %q: invalid port", str)
+	}
+	return HostPort{Host: host, Port: int(n)}, nil
+}
+
+// String returns the address in the form of "host:port".
+func (hp HostPort) String() string {
+	return net.JoinHostPort(hp.Host, strconv.Itoa(hp.Port))
+}
+
+// parseIP parses an IPv4 or IPv6 address.
+func parseIP(str string) (net.IP, error) {
+	ip := net.ParseIP(strings.TrimSpace(str))
+	if ip == nil {
+		return nil, fmt.Errorf("parsing %q: invalid IP address", str)
+	}
+	return ip, nil
+}
+
+// parseIPNet parses an IP network in CIDR notation, and returns the network
+// rather than the address, e.g. "10.0.0.0/8" for "10.1.2.3/8".
+func parseIPNet(str string) (*net.IPNet, error) {
+	_, ipNet, err := net.ParseCIDR(strings.TrimSpace(str))
+	return ipNet, err
+}
+
+// parseURL parses a non-empty URL.
+func parseURL(str string) (*url.URL, error) {
+	str = strings.TrimSpace(str)
+	if len(str) == 0 {
+		return nil, fmt.Errorf("parsing %q: empty URL", str)
+	}
+	return url.Parse(str)
+}
+
+// parseHardwareAddr parses a MAC address.
+func parseHardwareAddr(str string) (net.HardwareAddr, error) {
+	return net.ParseMAC(strings.TrimSpace(str))
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHostPort(t *testing.T) {
+	tests := []struct {
+		in   string
+		want HostPort
+	}{
+		{in: "localhost:8080", want: HostPort{Host: "localhost", Port: 8080}},
+		{in: " 127.0.0.1:80 ", want: HostPort{Host: "127.0.0.1", Port: 80}},
+		{in: "[::1]:443", want: HostPort{Host: "::1", Port: 443}},
+		{in: ":0", want: HostPort{Port: 0}},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := ParseHostPort(test.in)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	for _, in := range []string{"", "localhost", "localhost:http", "localhost:65536", "::1:80"} {
+		_, err := ParseHostPort(in)
+		assert.Error(t, err, in)
+	}
+
+	assert.Equal(t, "localhost:8080", HostPort{Host: "localhost", Port: 8080}.String())
+	assert.Equal(t, "[::1]:443", HostPort{Host: "::1", Port: 443}.String())
+}
+
+func TestKey_Network(t *testing.T) {
+	f, err := Load([]byte(`
+ip = 192.168.1.10
+ipv6 = ::1
+subnet = 10.1.2.3/8
+listen = 0.0.0.0:8080
+endpoint = https://example.com/api?v=1
+mac = 00:1a:2b:3c:4d:5e
+ips = 10.0.0.1, bad, ::1
+subnets = 10.0.0.0/8, bad
+addrs = :80, bad, [::1]:443
+urls = http://a.example, , http://b.example
+macs = 00:1a:2b:3c:4d:5e, bad
+bad = not an address
+`))
+	require.NoError(t, err)
+	sec := f.Section("")
+
+	t.Run("get values", func(t *testing.T) {
+		ip, err := sec.Key("ip").IP()
+		require.NoError(t, err)
+		assert.True(t, net.IPv4(192, 168, 1, 10).Equal(ip))
+		assert.True(t, net.IPv6loopback.Equal(sec.Key("ipv6").MustIP()))
+
+		ipNet, err := sec.Key("subnet").IPNet()
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.0/8", ipNet.String())
+
+		hp, err := sec.Key("listen").HostPort()
+		require.NoError(t, err)
+		assert.Equal(t, HostPort{Host: "0.0.0.0", Port: 8080}, hp)
+
+		u, err := sec.Key("endpoint").URL()
+		require.NoError(t, err)
+		assert.Equal(t, "example.com", u.Host)
+		assert.Equal(t, "v=1", u.RawQuery)
+
+		mac, err := sec.Key("mac").HardwareAddr()
+		require.NoError(t, err)
+		assert.Equal(t, net.HardwareAddr{0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}, mac)
+
+		_, err = sec.Key("bad").IP()
+		assert.Error(t, err)
+		_, err = sec.Key("bad").IPNet()
+		assert.Error(t, err)
+		_, err = sec.Key("bad").HostPort()
+		assert.Error(t, err)
+		_, err = sec.Key("missing").URL()
+		assert.Error(t, err)
+		_, err = sec.Key("bad").HardwareAddr()
+		assert.Error(t, err)
+	})
+
+	t.Run("get values with defaults", func(t *testing.T) {
+		assert.Nil(t, sec.Key("bad").MustIP())
+		assert.True(t, net.IPv4(127, 0, 0, 1).Equal(sec.Key("missing_ip").MustIP(net.IPv4(127, 0, 0, 1))))
+		assert.Equal(t, "127.0.0.1", sec.Key("missing_ip").String())
+
+		_, def, err := net.ParseCIDR("192.168.0.0/16")
+		require.NoError(t, err)
+		assert.Equal(t, def, sec.Key("missing_subnet").MustIPNet(def))
+		assert.Equal(t, "192.168.0.0/16", sec.Key("missing_subnet").String())
+
+		hp := HostPort{Host: "::1", Port: 80}
+		assert.Equal(t, hp, sec.Key("missing_listen").MustHostPort(hp))
+		assert.Equal(t, "[::1]:80", sec.Key("missing_listen").String())
+
+		u := &url.URL{Scheme: "http", Host: "localhost"}
+		assert.Equal(t, u, sec.Key("missing_endpoint").MustURL(u))
+		assert.Equal(t, "http://localhost", sec.Key("missing_endpoint").String())
+
+		mac := net.HardwareAddr{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}
+		assert.Equal(t, mac, sec.Key("missing_mac").MustHardwareAddr(mac))
+		assert.Equal(t, "aa:bb:cc:dd:ee:ff", sec.Key("missing_mac").String())
+	})
+
+	t.Run("get slices", func(t *testing.T) {
+		ips := sec.Key("ips").IPs(",")
+		require.Len(t, ips, 3)
+		assert.Nil(t, ips[1])
+		assert.Len(t, sec.Key("ips").ValidIPs(","), 2)
+		_, err := sec.Key("ips").StrictIPs(",")
+		assert.Error(t, err)
+
+		assert.Len(t, sec.Key("subnets").IPNets(","), 2)
+		assert.Len(t, sec.Key("subnets").ValidIPNets(","), 1)
+		_, err = sec.Key("subnets").StrictIPNets(",")
+		assert.Error(t, err)
+
+		assert.Equal(t, []HostPort{{Port: 80}, {}, {Host: "::1", Port: 443}}, sec.Key("addrs").HostPorts(","))
+		assert.Equal(t, []HostPort{{Port: 80}, {Host: "::1", Port: 443}}, sec.Key("addrs").ValidHostPorts(","))
+		_, err = sec.Key("addrs").StrictHostPorts(",")
+		assert.Error(t, err)
+
+		urls := sec.Key("urls").ValidURLs(",")
+		require.Len(t, urls, 2)
+		assert.Equal(t, "b.example", urls[1].Host)
+		assert.Len(t, sec.Key("urls").URLs(","), 3)
+		_, err = sec.Key("urls").StrictURLs(",")
+		assert.Error(t, err)
+
+		assert.Len(t, sec.Key("macs").HardwareAddrs(","), 2)
+		assert.Len(t, sec.Key("macs").ValidHardwareAddrs(","), 1)
+		_, err = sec.Key("macs").StrictHardwareAddrs(",")
+		assert.Error(t, err)
+	})
+}
+
+func TestMapToAndReflectFromNetwork(t *testing.T) {
+	type server struct {
+		IP       net.IP           `ini:"ip"`
+		Subnet   net.IPNet        `ini:"subnet"`
+		Allow    []net.IP         `ini:"allow"`
+		Listen   HostPort         `ini:"listen"`
+		Peers    []HostPort       `ini:"peers"`
+		Endpoint *url.URL         `ini:"endpoint"`
+		Homepage url.URL          `ini:"homepage"`
+		MAC      net.HardwareAddr `ini:"mac"`
+		Unset    *url.URL         `ini:"unset,omitempty"`
+	}
+
+	f, err := Load([]byte(`
+[server]
+ip = 10.0.0.1
+subnet = 10.0.0.0/8
+allow = 10.0.0.2, ::1
+listen = :8080
+peers = a:1, b:2
+endpoint = https://example.com/api
+homepage = https://example.com
+mac = 00:1a:2b:3c:4d:5e
+`))
+	require.NoError(t, err)
+
+	s := new(server)
+	require.NoError(t, f.Section("server").StrictMapTo(s))
+	assert.Equal(t, "10.0.0.1", s.IP.String())
+	assert.Equal(t, "10.0.0.0/8", s.Subnet.String())
+	assert.Equal(t, []net.IP{net.ParseIP("10.0.0.2"), net.ParseIP("::1")}, s.Allow)
+	assert.Equal(t, HostPort{Port: 8080}, s.Listen)
+	assert.Equal(t, []HostPort{{Host: "a", Port: 1}, {Host: "b", Port: 2}}, s.Peers)
+	require.NotNil(t, s.Endpoint)
+	assert.Equal(t, "/api", s.Endpoint.Path)
+	assert.Equal(t, "example.com", s.Homepage.Host)
+	assert.Equal(t, "00:1a:2b:3c:4d:5e", s.MAC.String())
+	assert.Nil(t, s.Unset)
+
+	bad, err := Load([]byte("ip = 10.0.0"))
+	require.NoError(t, err)
+	assert.Error(t, bad.StrictMapTo(s))
+
+	cfg := Empty()
+	require.NoError(t, cfg.Section("server").ReflectFrom(s))
+	var buf bytes.Buffer
+	_, err = cfg.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, `[server]
+ip       = 10.0.0.1
+subnet   = 10.0.0.0/8
+allow    = 10.0.0.2,::1
+listen   = :8080
+peers    = a:1,b:2
+endpoint = https://example.com/api
+homepage = https://example.com
+mac      = 00:1a:2b:3c:4d:5e
+`, buf.String())
+}
@@ -17,10 +17,10 @@ package ini
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"regexp"
-	"strconv"
 	"strings"
 	"unicode"
 )
@@ -37,6 +37,8 @@ type parserOptions struct {
 	UnescapeValueDoubleQuotes   bool
 	UnescapeValueCommentSymbols bool
 	PreserveSurroundedQuote     bool
+	StrictPythonMultilineValues bool
+	EmptyLinesInValues          bool
 	DebugFunc                   DebugFunc
 	ReaderBufferSize            int
 }
@@ -45,9 +47,16 @@ type parser struct {
 	buf     *bufio.Reader
 	options parserOptions
 
-	isEOF   bool
-	count   int
-	comment *bytes.Buffer
+	isEOF bool
+	count int
+	// The inline comment following the value of current line.
+	inlineComment string
+	// The indentation of current line, used by strict Python-like multi-line values.
+	indent int
+	// The number of lines read so far.
+	lineNum int
+	// The buffer reused for lines longer than the reader buffer.
+	line []byte
 }
 
 func (p *parser) debug(format string, args ...interface{}) {
@@ -66,7 +75,6 @@ func newParser(r io.Reader, opts parserOptions) *parser {
 		buf:     bufio.NewReaderSize(r, size),
 		options: opts,
 		count:   1,
-		comment: &bytes.Buffer{},
 	}
 }
 
@@ -105,8 +113,19 @@ func (p *parser) BOM() error {
 	return nil
 }
 
+// readUntil reads until the first occurrence of delim. The returned data is only
+// valid until the next read, callers must copy what they keep.
 func (p *parser) readUntil(delim byte) ([]byte, error) {
-	data, err := p.buf.ReadBytes(delim)
+	data, err := p.buf.ReadSlice(delim)
+	if err == bufio.ErrBufferFull {
+		// Collect lines longer than the reader buffer in the reused line buffer.
+		p.line = append(p.line[:0], data...)
+		for err == bufio.ErrBufferFull {
+			data, err = p.buf.ReadSlice(delim)
+			p.line = append(p.line, data...)
+		}
+		data = p.line
+	}
 	if err != nil {
 		if err == io.EOF {
 			p.isEOF = true
@@ -114,9 +133,22 @@ func (p *parser) readUntil(delim byte) ([]byte, error) {
 			return nil, err
 		}
 	}
+	if len(data) > 0 {
+		p.lineNum++
+	}
 	return data, nil
 }
 
+// discard skips given data that has been peeked.
+func (p *parser) discard(data []byte) error {
+	_, err := p.buf.Discard(len(data))
+	if err != nil {
+		return err
+	}
+	p.lineNum += bytes.Count(data, []byte{'\n'})
+	return nil
+}
+
 func cleanComment(in []byte) ([]byte, bool) {
 	i := bytes.IndexAny(in, "#;")
 	if i == -1 {
@@ -125,9 +157,8 @@ func cleanComment(in []byte) ([]byte, bool) {
 	return in[i:], true
 }
 
-func readKeyName(delimiters string, in []byte) (string, int, error) {
-	line := string(in)
-
+// readKeyName returns the key name and the offset of its value in the line.
+func readKeyName(delimiters, line string) (string, int, error) {
 	// Check if key name surrounded by quotes.
 	var keyQuote string
 	if line[0] == '"' {
@@ -172,6 +203,8 @@ func readKeyName(delimiters string, in []byte) (string, int, error) {
 }
 
 func (p *parser) readMultilines(line, val, valQuote string) (string, error) {
+	var buf strings.Builder
+	buf.WriteString(val)
 	for {
 		data, err := p.readUntil('\n')
 		if err != nil {
@@ -181,23 +214,25 @@ func (p *parser) readMultilines(line, val, valQuote string) (string, error) {
 
 		pos := strings.LastIndex(next, valQuote)
 		if pos > -1 {
-			val += next[:pos]
+			buf.WriteString(next[:pos])
 
 			comment, has := cleanComment([]byte(next[pos:]))
 			if has {
-				p.comment.Write(bytes.TrimSpace(comment))
+				p.inlineComment = string(bytes.TrimSpace(comment))
 			}
 			break
 		}
-		val += next
+		buf.WriteString(next)
 		if p.isEOF {
 			return "", fmt.Errorf("missing closing key quote from %q to %q", line, next)
 		}
 	}
-	return val, nil
+	return buf.String(), nil
 }
 
 func (p *parser) readContinuationLines(val string) (string, error) {
+	var buf strings.Builder
+	buf.WriteString(val)
 	for {
 		data, err := p.readUntil('\n')
 		if err != nil {
@@ -208,13 +243,13 @@ func (p *parser) readContinuationLines(val string) (string, error) {
 		if len(next) == 0 {
 			break
 		}
-		val += next
-		if val[len(val)-1] != '\\' {
+		if next[len(next)-1] != '\\' {
+			buf.WriteString(next)
 			break
 		}
-		val = val[:len(val)-1]
+		buf.WriteString(next[:len(next)-1])
 	}
-	return val, nil
+	return buf.String(), nil
 }
 
 // hasSurroundedQuote check if and only if the first and last characters
@@ -225,9 +260,9 @@ func hasSurroundedQuote(in string, quote byte) bool {
 		strings.IndexByte(in[1:], quote) == len(in)-2
 }
 
-func (p *parser) readValue(in []byte, bufferSize int) (string, error) {
+func (p *parser) readValue(in string, bufferSize int) (string, error) {
 
-	line := strings.TrimLeftFunc(string(in), unicode.IsSpace)
+	line := strings.TrimLeftFunc(in, unicode.IsSpace)
 	if len(line) == 0 {
 		if p.options.AllowPythonMultilineValues && len(in) > 0 && in[len(in)-1] == '\n' {
 			return p.readPythonMultilines(line, bufferSize)
@@ -244,6 +279,8 @@ func (p *parser) readValue(in []byte, bufferSize int) (string, error) {
 		valQuote = `"`
 	}
 
+	// Quotes are removed by slicing the line, so values are not copied again
+	// unless escape sequences have to be replaced.
 	if len(valQuote) > 0 {
 		startIdx := len(valQuote)
 		pos := strings.LastIndex(line[startIdx:], valQuote)
@@ -252,6 +289,13 @@ func (p *parser) readValue(in []byte, bufferSize int) (string, error) {
 			return p.readMultilines(line, line[startIdx:], valQuote)
 		}
 
+		if !p.options.IgnoreInlineComment {
+			rest := line[startIdx+pos+len(valQuote):]
+			if i := strings.IndexAny(rest, "#;"); i > -1 {
+				p.inlineComment = strings.TrimSpace(rest[i:])
+			}
+		}
+
 		if p.options.UnescapeValueDoubleQuotes && valQuote == `"` {
 			return strings.Replace(line[startIdx:pos+startIdx], `\"`, `"`, -1), nil
 		}
@@ -282,7 +326,7 @@ func (p *parser) readValue(in []byte, bufferSize int) (string, error) {
 		}
 
 		if i > -1 {
-			p.comment.WriteString(line[i:])
+			p.inlineComment = strings.TrimSpace(line[i:])
 			line = strings.TrimSpace(line[:i])
 		}
 
@@ -303,9 +347,15 @@ func (p *parser) readValue(in []byte, bufferSize int) (string, error) {
 }
 
 func (p *parser) readPythonMultilines(line string, bufferSize int) (string, error) {
+	if p.options.StrictPythonMultilineValues {
+		return p.readStrictPythonMultilines(line, bufferSize)
+	}
+
 	parserBufferPeekResult, _ := p.buf.Peek(bufferSize)
 	peekBuffer := bytes.NewBuffer(parserBufferPeekResult)
 
+	var buf strings.Builder
+	buf.WriteString(line)
 	for {
 		peekData, peekErr := peekBuffer.ReadBytes('\n')
 		if peekErr != nil && peekErr != io.EOF {
@@ -323,198 +373,157 @@ func (p *parser) readPythonMultilines(line string, bufferSize int) (string, erro
 
 		// Return if not a Python multiline value.
 		if len(peekMatches) != 3 {
-			p.debug("readPythonMultilines: end of value, got: %q", line)
-			return line, nil
+			p.debug("readPythonMultilines: end of value, got: %q", buf.String())
+			return buf.String(), nil
 		}
 
 		// Advance the parser reader (buffer) in-sync with the peek buffer.
-		_, err := p.buf.Discard(len(peekData))
+		err := p.discard(peekData)
 		if err != nil {
 			p.debug("readPythonMultilines: failed to skip to the end, returning error")
 			return "", err
 		}
 
-		line += "\n" + peekMatches[0]
+		buf.WriteByte('\n')
+		buf.WriteString(peekMatches[0])
 	}
 }
 
-// parse parses data through an io.Reader.
-func (f *File) parse(reader io.Reader) (err error) {
-	p := newParser(reader, parserOptions{
-		IgnoreContinuation:          f.options.IgnoreContinuation,
-		IgnoreInlineComment:         f.options.IgnoreInlineComment,
-		AllowPythonMultilineValues:  f.options.AllowPythonMultilineValues,
-		SpaceBeforeInlineComment:    f.options.SpaceBeforeInlineComment,
-		UnescapeValueDoubleQuotes:   f.options.UnescapeValueDoubleQuotes,
-		UnescapeValueCommentSymbols: f.options.UnescapeValueCommentSymbols,
-		PreserveSurroundedQuote:     f.options.PreserveSurroundedQuote,
-		DebugFunc:                   f.options.DebugFunc,
-		ReaderBufferSize:            f.options.ReaderBufferSize,
-	})
-	if err = p.BOM(); err != nil {
-		return fmt.Errorf("BOM: %v", err)
-	}
-
-	// Ignore error because default section name is never empty string.
-	name := DefaultSection
-	if f.options.Insensitive || f.options.InsensitiveSections {
-		name = strings.ToLower(DefaultSection)
-	}
-	section, _ := f.NewSection(name)
-
-	// This "last" is not strictly equivalent to "previous one" if current key is not the first nested key
-	var isLastValueEmpty bool
-	var lastRegularKey *Key
-
-	var line []byte
-	var inUnparseableSection bool
-
-	// NOTE: Iterate and increase `currentPeekSize` until
-	// the size of the parser buffer is found.
-	// TODO(unknwon): When Golang 1.10 is the lowest version supported, replace with `parserBufferSize := p.buf.Size()`.
-	parserBufferSize := 0
-	// NOTE: Peek 4kb at a time.
-	currentPeekSize := minReaderBufferSize
-
-	if f.options.AllowPythonMultilineValues {
+// readStrictPythonMultilines reads continuation lines the same way as Python's
+// configparser: a continuation line must be indented deeper than the key, comment
+// lines in between are skipped and empty lines are kept only when followed by
+// another continuation line.
+func (p *parser) readStrictPythonMultilines(line string, bufferSize int) (string, error) {
+	var buf strings.Builder
+	buf.WriteString(line)
+	for {
+		parserBufferPeekResult, _ := p.buf.Peek(bufferSize)
+		peekBuffer := bytes.NewBuffer(parserBufferPeekResult)
+
+		// Look ahead for the next continuation line without consuming anything
+		// in case the value has ended.
+		var next []byte
+		skipped, emptyLines := 0, 0
+	Lookahead:
 		for {
-			peekBytes, _ := p.buf.Peek(currentPeekSize)
-			peekBytesLength := len(peekBytes)
-
-			if parserBufferSize >= peekBytesLength {
+			peekData, peekErr := peekBuffer.ReadBytes('\n')
+			if len(peekData) == 0 {
 				break
 			}
 
-			currentPeekSize *= 2
-			parserBufferSize = peekBytesLength
-		}
-	}
-
-	for !p.isEOF {
-		line, err = p.readUntil('\n')
-		if err != nil {
-			return err
-		}
-
-		if f.options.AllowNestedValues &&
-			isLastValueEmpty && len(line) > 0 {
-			if line[0] == ' ' || line[0] == '\t' {
-				err = lastRegularKey.addNestedValue(string(bytes.TrimSpace(line)))
-				if err != nil {
-					return err
+			trimmed := bytes.TrimSpace(peekData)
+			switch {
+			case len(trimmed) == 0:
+				if !p.options.EmptyLinesInValues {
+					break Lookahead
+				}
+				emptyLines++
+			case trimmed[0] == '#' || trimmed[0] == ';':
+			default:
+				indent := len(peekData) - len(bytes.TrimLeftFunc(peekData, unicode.IsSpace))
+				if indent > p.indent {
+					next = peekData
 				}
-				continue
+				break Lookahead
 			}
-		}
 
-		line = bytes.TrimLeftFunc(line, unicode.IsSpace)
-		if len(line) == 0 {
-			continue
+			skipped += len(peekData)
+			if peekErr != nil {
+				break
+			}
 		}
 
-		// Comments
-		if line[0] == '#' || line[0] == ';' {
-			// Note: we do not care ending line break,
-			// it is needed for adding second line,
-			// so just clean it once at the end when set to value.
-			p.comment.Write(line)
-			continue
+		if next == nil {
+			p.debug("readStrictPythonMultilines: end of value, got: %q", buf.String())
+			return buf.String(), nil
 		}
 
-		// Section
-		if line[0] == '[' {
-			// Read to the next ']' (TODO: support quoted strings)
-			closeIdx := bytes.LastIndexByte(line, ']')
-			if closeIdx == -1 {
-				return fmt.Errorf("unclosed section: %s", line)
-			}
-
-			name := string(line[1:closeIdx])
-			section, err = f.NewSection(name)
-			if err != nil {
-				return err
-			}
+		if err := p.discard(parserBufferPeekResult[:skipped+len(next)]); err != nil {
+			p.debug("readStrictPythonMultilines: failed to skip to the end, returning error")
+			return "", err
+		}
+		buf.WriteString(strings.Repeat("\n", emptyLines+1))
+		buf.Write(bytes.TrimSpace(next))
+	}
+}
 
-			comment, has := cleanComment(line[closeIdx+1:])
-			if has {
-				p.comment.Write(comment)
-			}
+// parse parses data through an io.Reader. The caller must hold the lock.
+func (f *File) parse(reader io.Reader) (err error) {
+	s := NewScanner(reader, f.options)
 
-			section.Comment = strings.TrimSpace(p.comment.String())
+	section := f.newSection(DefaultSection)
 
-			// Reset auto-counter and comments
-			p.comment.Reset()
-			p.count = 1
-			// Nested values can't span sections
-			isLastValueEmpty = false
+	var comments []string
+	takeComment := func() string {
+		comment := strings.Join(comments, LineBreak)
+		comments = comments[:0]
+		return comment
+	}
 
-			inUnparseableSection = false
-			for i := range f.options.UnparseableSections {
-				if f.options.UnparseableSections[i] == name ||
-					((f.options.Insensitive || f.options.InsensitiveSections) && strings.EqualFold(f.options.UnparseableSections[i], name)) {
-					inUnparseableSection = true
-					continue
-				}
+	// This "last" is not strictly equivalent to "previous one" if current key is not the first nested key
+	var lastRegularKey *Key
+	for s.Scan() {
+		tok := s.Token()
+		switch tok.Type {
+		case TokenComment:
+			comments = append(comments, tok.Value)
+
+		case TokenSectionStart:
+			if len(tok.Name) == 0 {
+				return errors.New("empty section name")
 			}
-			continue
-		}
+			section = f.newSection(tok.Name)
+			if first := f.sections[section.name][0]; f.options.PreserveCase && first.spelling != tok.Name {
+				return ErrCaseConflict{Name: first.spelling, Conflict: tok.Name}
+			}
+			section.Comment = takeComment()
+			section.InlineComment = tok.InlineComment
 
-		if inUnparseableSection {
+		case TokenRawLine:
 			section.isRawSection = true
-			section.rawBody += string(line)
-			continue
-		}
+			section.rawBody += tok.Value
 
-		kname, offset, err := readKeyName(f.options.KeyValueDelimiters, line)
-		if err != nil {
-			switch {
-			// Treat as boolean key when desired, and whole line is key name.
-			case IsErrDelimiterNotFound(err):
-				switch {
-				case f.options.AllowBooleanKeys:
-					kname, err := p.readValue(line, parserBufferSize)
-					if err != nil {
-						return err
-					}
-					key, err := section.NewBooleanKey(kname)
-					if err != nil {
-						return err
-					}
-					key.Comment = strings.TrimSpace(p.comment.String())
-					p.comment.Reset()
-					continue
-
-				case f.options.SkipUnrecognizableLines:
-					continue
-				}
-			case IsErrEmptyKeyName(err) && f.options.SkipUnrecognizableLines:
-				continue
+		case TokenNestedValue:
+			if err = lastRegularKey.addNestedValue(tok.Value); err != nil {
+				return err
 			}
-			return err
-		}
-
-		// Auto increment.
-		isAutoIncr := false
-		if kname == "-" {
-			isAutoIncr = true
-			kname = "#" + strconv.Itoa(p.count)
-			p.count++
-		}
 
-		value, err := p.readValue(line[offset:], parserBufferSize)
-		if err != nil {
-			return err
+		case TokenKey:
+			var key *Key
+			if tok.IsBoolean {
+				key, err = section.newBooleanKey(tok.Name)
+			} else {
+				key, err = section.newKey(tok.Name, tok.Value)
+			}
+			if err != nil {
+				return err
+			}
+			if f.options.PreserveCase && key.spelling != tok.Name {
+				return ErrCaseConflict{Name: key.spelling, Conflict: tok.Name}
+			}
+			key.isAutoIncrement = tok.IsAutoIncrement
+			// Keys defined again, e.g. shadows, keep their comments unless given new ones.
+			if comment := takeComment(); len(comment) > 0 {
+				key.Comment = comment
+			}
+			if len(tok.InlineComment) > 0 {
+				key.InlineComment = tok.InlineComment
+			}
+			if !tok.IsBoolean {
+				lastRegularKey = key
+			}
 		}
-		isLastValueEmpty = len(value) == 0
+	}
+	if err = s.Err(); err != nil {
+		return err
+	}
 
-		key, err := section.NewKey(kname, value)
-		if err != nil {
-			return err
+	// Comments after the last key are kept as the footer of the file.
+	if footer := takeComment(); len(footer) > 0 {
+		if len(f.FooterComment) > 0 {
+			footer = f.FooterComment + LineBreak + footer
 		}
-		key.isAutoIncrement = isAutoIncr
-		key.Comment = strings.TrimSpace(p.comment.String())
-		p.comment.Reset()
-		lastRegularKey = key
+		f.FooterComment = footer
 	}
 	return nil
 }
@@ -15,6 +15,7 @@
 package ini
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -75,3 +76,47 @@ func TestBadLoad(t *testing.T) {
 		})
 	})
 }
+
+func TestLongLines(t *testing.T) {
+	t.Run("lines longer than the reader buffer", func(t *testing.T) {
+		long := strings.Repeat("0123456789", 2000)
+		data := "[section]\n" +
+			"key1 = " + long + " # comment\n" +
+			"key2 = `" + long + "`\n" +
+			"key3 = \"\"\"" + long + "\n" + long + "\"\"\"\n" +
+			"key4 = short\n"
+
+		f, err := LoadSources(LoadOptions{ReaderBufferSize: minReaderBufferSize}, []byte(data))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		sec := f.Section("section")
+		assert.Equal(t, long, sec.Key("key1").String())
+		assert.Equal(t, "# comment", sec.Key("key1").InlineComment)
+		assert.Equal(t, long, sec.Key("key2").String())
+		assert.Equal(t, long+"\n"+long, sec.Key("key3").String())
+		assert.Equal(t, "short", sec.Key("key4").String())
+	})
+}
+
+func TestParser_ReadValue(t *testing.T) {
+	t.Run("unquoting does not copy values", func(t *testing.T) {
+		p := newParser(strings.NewReader(""), parserOptions{UnescapeValueDoubleQuotes: true})
+		for _, in := range []string{
+			"`value` # comment",
+			`"""value"""`,
+			`"value"`,
+			`'value' ; comment`,
+			`value # comment`,
+		} {
+			var val string
+			var err error
+			allocs := testing.AllocsPerRun(100, func() {
+				val, err = p.readValue(in, minReaderBufferSize)
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "value", val)
+			assert.Equal(t, float64(0), allocs, in)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"strings"
+)
+
+// WithProfile returns a view of the file for given chain of profiles, in which
+// keys of profile sections override keys of their base sections one by one,
+// e.g. "[database:prod]" overrides "[database]" with the default format of
+// LoadOptions.ProfileSectionFormat. Profiles come first in the chain take
+// precedence over the ones after them, and the base sections come last.
+//
+// Profile sections of the chain are merged into their base sections and do not
+// appear in the view on their own. Profile sections without base sections
+// become base sections in the view. The view is a deep copy that has no data
+// sources, and is not affected by later changes of the file.
+func (f *File) WithProfile(profiles ...string) *File {
+	if f.BlockMode {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
+	}
+
+	c := f.emptyCopy(nil)
+	list := make([]*Section, 0, len(f.sectionList))
+	// Profile sections of each base section by their positions in the chain.
+	overrides := make(map[string][]*Section)
+	for _, sec := range f.sectionsInOrder() {
+		base, rank, ok := f.parseProfileSection(sec.name, profiles)
+		if !ok {
+			list = append(list, sec.clone(c, sec.name))
+			continue
+		}
+
+		if overrides[base] == nil {
+			overrides[base] = make([]*Section, len(profiles))
+			if len(f.sections[base]) == 0 {
+				list = append(list, newSection(c, base))
+			}
+		}
+		// The first one wins for non-unique sections, as File.GetSection does.
+		if overrides[base][rank] == nil {
+			overrides[base][rank] = sec
+		}
+	}
+
+	for base, secs := range overrides {
+		target := list[indexOfSection(list, base)]
+		for i := len(secs) - 1; i >= 0; i-- {
+			if secs[i] == nil {
+				continue
+			}
+			if secs[i].isRawSection {
+				target.isRawSection = true
+				target.rawBody = secs[i].rawBody
+			}
+			target.copyKeys(secs[i])
+		}
+	}
+	c.setSections(list)
+	return c
+}
+
+// parseProfileSection returns the normalized name of the base section and the
+// position of the profile in the chain if the section with given name is a
+// profile section of any profile in the chain.
+func (f *File) parseProfileSection(name string, profiles []string) (base string, rank int, ok bool) {
+	insensitive := f.options.Insensitive || f.options.InsensitiveSections
+	for i, profile := range profiles {
+		if insensitive {
+			profile = foldCase(profile, f.options.UnicodeCaseFolding)
+		}
+		format := strings.Replace(f.options.ProfileSectionFormat, "{profile}", profile, -1)
+		idx := strings.Index(format, "{name}")
+		if idx == -1 {
+			continue
+		}
+		prefix, suffix := format[:idx], format[idx+len("{name}"):]
+		if len(name) < len(prefix)+len(suffix) ||
+			!strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		return f.sectionName(name[len(prefix) : len(name)-len(suffix)]), i, true
+	}
+	return "", 0, false
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_WithProfile(t *testing.T) {
+	const data = `
+app = demo
+[database]
+host = localhost
+port = 5432
+user = dev
+[database:staging]
+host = staging.local
+user = stage
+[database:prod]
+host = db.prod
+[cache:prod]
+size = 100
+[:prod]
+app = demo-prod
+`
+
+	t.Run("override base sections", func(t *testing.T) {
+		f, err := Load([]byte(data))
+		require.NoError(t, err)
+
+		view := f.WithProfile("prod")
+		assert.Equal(t, []string{DefaultSection, "database", "database:staging", "cache"}, view.SectionStrings())
+		assert.Equal(t, "demo-prod", view.Section("").Key("app").String())
+		assert.Equal(t, map[string]string{
+			"host": "db.prod",
+			"port": "5432",
+			"user": "dev",
+		}, view.Section("database").KeysHash())
+		assert.Equal(t, "100", view.Section("cache").Key("size").String())
+
+		// The file is not affected
+		assert.Equal(t, "localhost", f.Section("database").Key("host").String())
+		view.Section("database").Key("host").SetValue("changed")
+		assert.Equal(t, "localhost", f.Section("database").Key("host").String())
+	})
+
+	t.Run("profile chain", func(t *testing.T) {
+		f, err := Load([]byte(data))
+		require.NoError(t, err)
+
+		view := f.WithProfile("prod", "staging")
+		assert.Equal(t, []string{DefaultSection, "database", "cache"}, view.SectionStrings())
+		assert.Equal(t, []string{"host", "port", "user"}, view.Section("database").KeyStrings())
+		assert.Equal(t, "db.prod", view.Section("database").Key("host").String())
+		assert.Equal(t, "stage", view.Section("database").Key("user").String())
+
+		view = f.WithProfile()
+		assert.Equal(t, f.SectionStrings(), view.SectionStrings())
+	})
+
+	t.Run("custom format", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{
+			Insensitive:          true,
+			ProfileSectionFormat: "{profile}.{name}",
+		}, []byte(`
+[Server]
+Host = localhost
+[Prod.Server]
+HOST = example.com
+`))
+		require.NoError(t, err)
+
+		view := f.WithProfile("PROD")
+		assert.Equal(t, []string{"default", "server"}, view.SectionStrings())
+		assert.Equal(t, "example.com", view.Section("server").Key("host").String())
+	})
+
+	t.Run("map to struct", func(t *testing.T) {
+		f, err := Load([]byte(data))
+		require.NoError(t, err)
+
+		type config struct {
+			App      string `ini:"app"`
+			Database struct {
+				Host string `ini:"host"`
+				Port int    `ini:"port"`
+				User string `ini:"user"`
+			} `ini:"database"`
+		}
+		cfg := new(config)
+		require.NoError(t, f.WithProfile("prod", "staging").MapTo(cfg))
+		assert.Equal(t, "demo-prod", cfg.App)
+		assert.Equal(t, "db.prod", cfg.Database.Host)
+		assert.Equal(t, 5432, cfg.Database.Port)
+		assert.Equal(t, "stage", cfg.Database.User)
+	})
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+// PythonBooleanStates returns the boolean states accepted by Python's configparser.
+// Docs: https://docs.python.org/3/library/configparser.html#configparser.ConfigParser.BOOLEAN_STATES
+func PythonBooleanStates() map[string]bool {
+	return map[string]bool{
+		"1": true, "yes": true, "true": true, "on": true,
+		"0": false, "no": false, "false": false, "off": false,
+	}
+}
+
+// PythonLoadOptions returns load options that make the parser behave like Python's
+// configparser.ConfigParser with ExtendedInterpolation and default settings.
+func PythonLoadOptions() LoadOptions {
+	return LoadOptions{
+		InsensitiveKeys:             true,
+		IgnoreContinuation:          true,
+		IgnoreInlineComment:         true,
+		PreserveSurroundedQuote:     true,
+		AllowPythonMultilineValues:  true,
+		StrictPythonMultilineValues: true,
+		EmptyLinesInValues:          true,
+		ExtendedInterpolation:       true,
+		InheritDefaultSection:       true,
+		FlatSections:                true,
+		BooleanStates:               PythonBooleanStates(),
+	}
+}
+
+// PythonLoad has exactly same functionality as Load function
+// except it applies options returned by PythonLoadOptions.
+func PythonLoad(source interface{}, others ...interface{}) (*File, error) {
+	return LoadSources(PythonLoadOptions(), source, others...)
+}
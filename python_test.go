@@ -0,0 +1,380 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Examples in this file are taken from https://docs.python.org/3/library/configparser.html
+// and expectations are what Python's configparser returns for them.
+
+func TestPythonLoad_QuickStart(t *testing.T) {
+	f, err := PythonLoad([]byte(`[DEFAULT]
+ServerAliveInterval = 45
+Compression = yes
+CompressionLevel = 9
+ForwardX11 = yes
+
+[forge.example]
+User = hg
+
+[topsecret.server.example]
+Port = 50022
+ForwardX11 = no
+`))
+	require.NoError(t, err)
+	require.NotNil(t, f)
+
+	assert.True(t, f.HasSection("forge.example"))
+	assert.False(t, f.HasSection("python.org"))
+	assert.Equal(t, "hg", f.Section("forge.example").Key("User").String())
+	assert.Equal(t, "yes", f.Section("DEFAULT").Key("Compression").String())
+
+	topsecret := f.Section("topsecret.server.example")
+	assert.Equal(t, "50022", topsecret.Key("Port").String())
+	assert.Equal(t, "no", topsecret.Key("ForwardX11").String())
+	assert.False(t, topsecret.Key("ForwardX11").MustBool(true))
+
+	forge := f.Section("forge.example")
+	assert.Equal(t, []string{"user", "serveraliveinterval", "compression", "compressionlevel", "forwardx11"}, forge.KeyStrings())
+	assert.Equal(t, "yes", forge.Key("ForwardX11").String())
+	assert.True(t, forge.HasKey("compressionlevel"))
+	assert.Equal(t, map[string]string{
+		"user":                "hg",
+		"serveraliveinterval": "45",
+		"compression":         "yes",
+		"compressionlevel":    "9",
+		"forwardx11":          "yes",
+	}, forge.KeysHash())
+
+	keys := forge.Keys()
+	require.Len(t, keys, 5)
+	assert.Equal(t, "9", keys[3].String())
+
+	t.Run("default section keys are not written to other sections", func(t *testing.T) {
+		assert.Equal(t, []string{"user"}, forge.keyList)
+	})
+}
+
+func TestPythonLoad_SupportedStructure(t *testing.T) {
+	opts := PythonLoadOptions()
+	opts.AllowBooleanKeys = true
+	f, err := LoadSources(opts, []byte(`[Simple Values]
+key=value
+spaces in keys=allowed
+spaces in values=allowed as well
+spaces around the delimiter = obviously
+you can also use : to delimit keys from values
+
+[All Values Are Strings]
+values like this: 1000000
+or this: 3.14159265359
+are they treated as numbers? : no
+integers, floats and booleans are held as: strings
+can use the API to get converted values directly: true
+
+[Multiline Values]
+chorus: I'm a lumberjack, and I'm okay
+    I sleep all night and I work all day
+
+[No Values]
+key_without_value
+empty string value here =
+
+[You can use comments]
+# like this
+; or this
+
+# By default only in an empty line.
+# Inline comments can be harmful because they prevent users
+# from using the delimiting characters as parts of values.
+# That being said, this can be customized.
+
+    [Sections Can Be Indented]
+        can_values_be_as_well = True
+        does_that_mean_anything_special = False
+        purpose = formatting for readability
+        multiline_values = are
+            handled just fine as
+            long as they are indented
+            deeper than the first line
+            of a value
+        # Did I mention we can indent comments, too?
+`))
+	require.NoError(t, err)
+	require.NotNil(t, f)
+
+	simple := f.Section("Simple Values")
+	assert.Equal(t, "value", simple.Key("key").String())
+	assert.Equal(t, "allowed", simple.Key("spaces in keys").String())
+	assert.Equal(t, "allowed as well", simple.Key("spaces in values").String())
+	assert.Equal(t, "obviously", simple.Key("spaces around the delimiter").String())
+	assert.Equal(t, "to delimit keys from values", simple.Key("you can also use").String())
+
+	strs := f.Section("All Values Are Strings")
+	assert.Equal(t, "1000000", strs.Key("values like this").String())
+	assert.Equal(t, 3.14159265359, strs.Key("or this").MustFloat64())
+	assert.Equal(t, "no", strs.Key("are they treated as numbers?").String())
+	assert.True(t, strs.Key("can use the API to get converted values directly").MustBool())
+
+	assert.Equal(t, "I'm a lumberjack, and I'm okay\nI sleep all night and I work all day",
+		f.Section("Multiline Values").Key("chorus").String())
+
+	noValues := f.Section("No Values")
+	assert.True(t, noValues.HasKey("key_without_value"))
+	assert.Equal(t, "", noValues.Key("empty string value here").String())
+
+	assert.Empty(t, f.Section("You can use comments").KeyStrings())
+
+	indented := f.Section("Sections Can Be Indented")
+	assert.True(t, indented.Key("can_values_be_as_well").MustBool())
+	assert.False(t, indented.Key("does_that_mean_anything_special").MustBool(true))
+	assert.Equal(t, "formatting for readability", indented.Key("purpose").String())
+	assert.Equal(t, "are\nhandled just fine as\nlong as they are indented\ndeeper than the first line\nof a value",
+		indented.Key("multiline_values").String())
+}
+
+func TestPythonLoad_EmptyLinesInValues(t *testing.T) {
+	data := []byte(`[section]
+key = first
+
+  second
+  # a comment
+
+  third
+
+
+other = value
+`)
+
+	t.Run("keep empty lines", func(t *testing.T) {
+		f, err := PythonLoad(data)
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "first\n\nsecond\n\nthird", f.Section("section").Key("key").String())
+		assert.Equal(t, "value", f.Section("section").Key("other").String())
+	})
+
+	t.Run("empty lines end values", func(t *testing.T) {
+		opts := PythonLoadOptions()
+		opts.EmptyLinesInValues = false
+		f, err := LoadSources(opts, []byte(`[section]
+key = first
+  second
+
+other = value
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "first\nsecond", f.Section("section").Key("key").String())
+		assert.Equal(t, "value", f.Section("section").Key("other").String())
+
+		// The indented line after an empty line is not a continuation line anymore.
+		_, err = LoadSources(opts, data)
+		require.Error(t, err)
+		assert.True(t, IsErrDelimiterNotFound(err))
+	})
+
+	t.Run("continuation lines must be indented deeper than the key", func(t *testing.T) {
+		f, err := PythonLoad([]byte(`[section]
+  key = first
+  other = value
+    second
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "first", f.Section("section").Key("key").String())
+		assert.Equal(t, "value\nsecond", f.Section("section").Key("other").String())
+	})
+}
+
+func TestPythonLoad_ExtendedInterpolation(t *testing.T) {
+	t.Run("references in the same section", func(t *testing.T) {
+		f, err := PythonLoad([]byte(`[Paths]
+home_dir: /Users
+my_dir: ${home_dir}/lumberjack
+my_pictures: ${my_dir}/Pictures
+
+[Escape]
+# use a $ to escape the $ sign ($ is the only character that needs to be escaped):
+cost: $$80
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "/Users/lumberjack", f.Section("Paths").Key("my_dir").String())
+		assert.Equal(t, "/Users/lumberjack/Pictures", f.Section("Paths").Key("my_pictures").String())
+		assert.Equal(t, "$80", f.Section("Escape").Key("cost").String())
+		assert.Equal(t, "$$80", f.Section("Escape").Key("cost").Value())
+	})
+
+	t.Run("references across sections", func(t *testing.T) {
+		f, err := PythonLoad([]byte(`[Common]
+home_dir: /Users
+library_dir: /Library
+system_dir: /System
+macports_dir: /opt/local
+
+[Frameworks]
+Python: 3.2
+path: ${Common:system_dir}/Library/Frameworks/
+
+[Arthur]
+nickname: Two Sheds
+last_name: Jackson
+my_dir: ${Common:home_dir}/twosheds
+my_pictures: ${my_dir}/Pictures
+python_dir: ${Frameworks:path}/Python/Versions/${Frameworks:Python}
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "/System/Library/Frameworks/", f.Section("Frameworks").Key("path").String())
+		arthur := f.Section("Arthur")
+		assert.Equal(t, "/Users/twosheds", arthur.Key("my_dir").String())
+		assert.Equal(t, "/Users/twosheds/Pictures", arthur.Key("my_pictures").String())
+		assert.Equal(t, "/System/Library/Frameworks//Python/Versions/3.2", arthur.Key("python_dir").String())
+	})
+
+	t.Run("references to the default section", func(t *testing.T) {
+		f, err := PythonLoad([]byte(`[DEFAULT]
+base = /srv
+
+[app]
+root = ${base}/app
+log = ${DEFAULT:base}/log
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "/srv/app", f.Section("app").Key("root").String())
+		assert.Equal(t, "/srv/log", f.Section("app").Key("log").String())
+	})
+
+	t.Run("default section values resolve in the requesting section", func(t *testing.T) {
+		f, err := PythonLoad([]byte(`[DEFAULT]
+home = /def
+path = ${home}/x
+loop = ${loop}
+
+[app]
+home = /app
+
+[other]
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "/def/x", f.Section("DEFAULT").Key("path").String())
+		assert.Equal(t, "/app/x", f.Section("app").Key("path").String())
+		assert.Equal(t, "/def/x", f.Section("other").Key("path").String())
+
+		_, err = f.Section("app").Key("loop").Resolve()
+		require.Error(t, err)
+		assert.True(t, IsErrReferenceCycle(err))
+
+		// Inherited keys are changed in the default section.
+		f.Section("app").Key("path").SetValue("${home}/y")
+		assert.Equal(t, "/def/y", f.Section("DEFAULT").Key("path").String())
+		assert.Equal(t, "/app/y", f.Section("app").Key("path").String())
+	})
+
+	t.Run("child sections do not inherit parent sections", func(t *testing.T) {
+		data := []byte(`[a]
+port = 1
+
+[a.b]
+ref = ${port}
+dotted = ${a.port}
+`)
+		f, err := PythonLoad(data)
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		sec := f.Section("a.b")
+		assert.False(t, sec.HasKey("port"))
+		assert.Equal(t, "${port}", sec.Key("ref").String())
+		assert.Equal(t, "${a.port}", sec.Key("dotted").String())
+
+		// Python's configparser raises InterpolationMissingOptionError.
+		opts := PythonLoadOptions()
+		opts.StrictInterpolation = true
+		_, err = LoadSources(opts, data)
+		require.Error(t, err)
+		_, err = LoadSources(opts, []byte("[a]\nport = 1\n\n[a.b]\ndotted = ${a.port}\n"))
+		require.Error(t, err)
+	})
+
+	t.Run("basic interpolation is not applied", func(t *testing.T) {
+		f, err := PythonLoad([]byte(`[section]
+name = value
+key = %(name)s ${missing} ${name
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "%(name)s ${missing} ${name", f.Section("section").Key("key").String())
+	})
+}
+
+func TestPythonLoad_BooleanStates(t *testing.T) {
+	f, err := PythonLoad([]byte(`[section]
+a = 1
+b = YES
+c = True
+d = oN
+e = 0
+f = No
+g = FALSE
+h = off
+i = t
+j = y
+`))
+	require.NoError(t, err)
+	require.NotNil(t, f)
+
+	sec := f.Section("section")
+	for _, name := range []string{"a", "b", "c", "d"} {
+		v, err := sec.Key(name).Bool()
+		require.NoError(t, err)
+		assert.True(t, v, name)
+	}
+	for _, name := range []string{"e", "f", "g", "h"} {
+		v, err := sec.Key(name).Bool()
+		require.NoError(t, err)
+		assert.False(t, v, name)
+	}
+	for _, name := range []string{"i", "j"} {
+		_, err := sec.Key(name).Bool()
+		assert.Error(t, err, name)
+	}
+
+	t.Run("map to struct", func(t *testing.T) {
+		var v struct {
+			B bool `ini:"b"`
+			G bool `ini:"g"`
+		}
+		require.NoError(t, sec.MapTo(&v))
+		assert.True(t, v.B)
+		assert.False(t, v.G)
+	})
+}
@@ -0,0 +1,356 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Query returns keys selected by given path expression, in the order of
+// sections and keys. An expression selects sections and then keys of them in
+// one of the following forms:
+//
+//	servers.*.port         sections by path of names, and the key after the last child delimiter
+//	[servers.*]/port       sections by glob of names, and the key after "/"
+//	section[name~="^db"]/* all sections filtered by predicates, and the key after "/"
+//
+// In paths, "*" matches one level of child sections and "**" matches any
+// number of levels. In globs of section and key names, "*" matches any
+// characters and "?" matches a single character. The keyword "section" only
+// selects all sections when followed by predicates.
+//
+// Sections and keys can be followed by predicates in brackets in the form of
+// `attr op "string"`, where attr is "name" or "value" (keys only), and op is
+// one of "=", "!=" and "~=" (matches regular expression). Keys can also be
+// followed by "[N]" to select the Nth value including shadows, negative N
+// counting from the last, or "[*]" to select all of them. Brackets are applied
+// in order, e.g. `port[*][value!="80"]` selects shadows of "port" whose values
+// are not "80". Values are compared after interpolation.
+func (f *File) Query(expr string) ([]*Key, error) {
+	q, err := f.parseQuery(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query %q: %v", expr, err)
+	}
+
+	var result []*Key
+	for _, key := range f.queryKeys(q) {
+		keys := []*Key{key}
+		for _, step := range q.keySteps {
+			keys = step.apply(key, keys)
+		}
+		result = append(result, keys...)
+	}
+	return result, nil
+}
+
+// query is a parsed path expression.
+type query struct {
+	section      *regexp.Regexp
+	sectionPreds []*queryPredicate
+	key          *regexp.Regexp
+	keySteps     []queryStep
+}
+
+// queryKeys returns keys matching names and name predicates of the query.
+func (f *File) queryKeys(q *query) []*Key {
+	if f.BlockMode {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
+	}
+
+	var keys []*Key
+	for _, sec := range f.sectionsInOrder() {
+		if !q.section.MatchString(sec.name) || !matchPredicates(q.sectionPreds, sec.displayName(), "") {
+			continue
+		}
+		for _, name := range sec.keyList {
+			if q.key.MatchString(name) {
+				keys = append(keys, sec.keys[name])
+			}
+		}
+	}
+	return keys
+}
+
+// queryPredicate is a predicate in brackets, e.g. `name~="^db"`.
+type queryPredicate struct {
+	attr  string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (p *queryPredicate) match(name, value string) bool {
+	s := name
+	if p.attr == "value" {
+		s = value
+	}
+	switch p.op {
+	case "=":
+		return s == p.value
+	case "!=":
+		return s != p.value
+	default:
+		return p.re.MatchString(s)
+	}
+}
+
+func matchPredicates(preds []*queryPredicate, name, value string) bool {
+	for _, p := range preds {
+		if !p.match(name, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// queryStep is a bracket following the key of a query.
+type queryStep struct {
+	all   bool
+	index int
+	pred  *queryPredicate
+}
+
+// apply returns keys selected by the step from given keys of the key. Indexes
+// always select from the values of the key including its shadows.
+func (step queryStep) apply(key *Key, keys []*Key) []*Key {
+	if step.pred == nil {
+		values := key.withShadows()
+		if step.all {
+			return values
+		}
+		i := step.index
+		if i < 0 {
+			i += len(values)
+		}
+		if i < 0 || i >= len(values) {
+			return nil
+		}
+		return values[i : i+1]
+	}
+
+	var result []*Key
+	for _, k := range keys {
+		if step.pred.match(k.Name(), k.String()) {
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+var queryPredicatePattern = regexp.MustCompile(`^\s*(name|value)\s*(=|!=|~=)\s*(".*"|` + "`.*`" + `)\s*$`)
+
+// parseQuery parses given path expression.
+func (f *File) parseQuery(expr string) (*query, error) {
+	expr = strings.TrimSpace(expr)
+	if len(expr) == 0 {
+		return nil, errors.New("empty expression")
+	}
+
+	var sectionPart, keyPart string
+	if i := indexTopLevel(expr, "/", false); i > -1 {
+		sectionPart, keyPart = expr[:i], expr[i+1:]
+	} else if i = indexTopLevel(expr, f.options.ChildSectionDelimiter, true); i > -1 {
+		sectionPart, keyPart = expr[:i], expr[i+len(f.options.ChildSectionDelimiter):]
+	} else {
+		sectionPart, keyPart = DefaultSection, expr
+	}
+
+	q := new(query)
+	head, items, err := splitBrackets(sectionPart)
+	if err != nil {
+		return nil, err
+	}
+	insensitiveSections := f.options.Insensitive || f.options.InsensitiveSections
+	switch {
+	case len(head) == 0 && len(items) > 0:
+		// [glob]
+		q.section = compileGlob(items[0], "", insensitiveSections, f.options.UnicodeCaseFolding)
+		items = items[1:]
+	case head == "section" && len(items) > 0:
+		// All sections filtered by predicates, so that "section" alone still
+		// selects the section of the name.
+		q.section = regexp.MustCompile("")
+	case len(head) > 0:
+		// Keep the default section as-is in paths.
+		if head == DefaultSection {
+			q.section = regexp.MustCompile("^" + regexp.QuoteMeta(f.sectionName(head)) + "$")
+		} else {
+			q.section = compileGlob(head, f.options.ChildSectionDelimiter, insensitiveSections, f.options.UnicodeCaseFolding)
+		}
+	default:
+		return nil, errors.New("empty section")
+	}
+	for _, item := range items {
+		p, err := parseQueryPredicate(item)
+		if err != nil {
+			return nil, err
+		} else if p == nil || p.attr != "name" {
+			return nil, fmt.Errorf("invalid section predicate %q", item)
+		}
+		q.sectionPreds = append(q.sectionPreds, p)
+	}
+
+	head, items, err = splitBrackets(keyPart)
+	if err != nil {
+		return nil, err
+	} else if len(head) == 0 {
+		return nil, errors.New("empty key")
+	}
+	q.key = compileGlob(head, "", f.options.Insensitive || f.options.InsensitiveKeys, f.options.UnicodeCaseFolding)
+	for _, item := range items {
+		step, err := parseQueryStep(item)
+		if err != nil {
+			return nil, err
+		}
+		q.keySteps = append(q.keySteps, step)
+	}
+	return q, nil
+}
+
+// parseQueryPredicate parses a predicate in brackets, and returns nil if the
+// item is not a predicate.
+func parseQueryPredicate(item string) (*queryPredicate, error) {
+	m := queryPredicatePattern.FindStringSubmatch(item)
+	if m == nil {
+		return nil, nil
+	}
+
+	value, err := strconv.Unquote(m[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid string %s: %v", m[3], err)
+	}
+	p := &queryPredicate{attr: m[1], op: m[2], value: value}
+	if p.op == "~=" {
+		if p.re, err = regexp.Compile(value); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// parseQueryStep parses a bracket following the key.
+func parseQueryStep(item string) (queryStep, error) {
+	p, err := parseQueryPredicate(item)
+	if err != nil {
+		return queryStep{}, err
+	} else if p != nil {
+		return queryStep{pred: p}, nil
+	}
+
+	item = strings.TrimSpace(item)
+	if item == "*" {
+		return queryStep{all: true}, nil
+	}
+	index, err := strconv.Atoi(item)
+	if err != nil {
+		return queryStep{}, fmt.Errorf("invalid index or predicate %q", item)
+	}
+	return queryStep{index: index}, nil
+}
+
+// splitBrackets splits given string into the head before the first bracket and
+// contents of brackets following it.
+func splitBrackets(s string) (head string, items []string, err error) {
+	i := strings.IndexByte(s, '[')
+	if i == -1 {
+		return s, nil, nil
+	}
+	head, s = s[:i], s[i:]
+
+	for len(s) > 0 {
+		if s[0] != '[' {
+			return "", nil, fmt.Errorf("unexpected %q after brackets", s)
+		}
+		end := indexTopLevel(s[1:], "]", false)
+		if end == -1 {
+			return "", nil, errors.New("unclosed bracket")
+		}
+		items = append(items, s[1:end+1])
+		s = s[end+2:]
+	}
+	return head, items, nil
+}
+
+// indexTopLevel returns the index of the first, or the last if last is true,
+// occurrence of sep in s that is neither in brackets nor quotes, or -1 if
+// there is none.
+func indexTopLevel(s, sep string, last bool) int {
+	idx := -1
+	depth := 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '`':
+			quote = c
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			if !last {
+				return i
+			}
+			idx = i
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+		}
+	}
+	return idx
+}
+
+// compileGlob compiles given glob into a regular expression matching whole
+// names. If delimiter is not empty, "*" and "?" do not match it and "**"
+// matches any characters.
+func compileGlob(glob, delimiter string, insensitive, unicodeFolding bool) *regexp.Regexp {
+	if insensitive {
+		glob = foldCase(glob, unicodeFolding)
+	}
+
+	many, one := ".*", "."
+	if len(delimiter) > 0 {
+		// Exclude the first byte of the delimiter, which is enough for common
+		// single-byte delimiters.
+		many = "[^" + regexp.QuoteMeta(delimiter[:1]) + "]*"
+		one = "[^" + regexp.QuoteMeta(delimiter[:1]) + "]"
+	}
+
+	var buf strings.Builder
+	buf.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch {
+		case strings.HasPrefix(glob[i:], "**"):
+			buf.WriteString(".*")
+			i++
+		case glob[i] == '*':
+			buf.WriteString(many)
+		case glob[i] == '?':
+			buf.WriteString(one)
+		default:
+			buf.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	buf.WriteString("$")
+	return regexp.MustCompile(buf.String())
+}
@@ -0,0 +1,138 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Query(t *testing.T) {
+	f, err := LoadSources(LoadOptions{AllowShadows: true}, []byte(`
+timeout = 10
+[servers]
+port = 80
+[servers.a]
+port = 8080
+host = a.local
+[servers.a.tls]
+port = 8443
+[servers.b]
+port = 9090
+port = 9091
+port = 9092
+[db.primary]
+timeout = 30
+dsn = "postgres://db/main"
+[dbcache]
+timeout = 5
+`))
+	require.NoError(t, err)
+
+	queryValues := func(t *testing.T, expr string) []string {
+		keys, err := f.Query(expr)
+		require.NoError(t, err)
+		values := make([]string, len(keys))
+		for i, key := range keys {
+			values[i] = key.Section().Name() + "/" + key.Name() + "=" + key.String()
+		}
+		return values
+	}
+
+	tests := []struct {
+		expr string
+		want []string
+	}{
+		{expr: "timeout", want: []string{"DEFAULT/timeout=10"}},
+		{expr: "servers.*.port", want: []string{"servers.a/port=8080", "servers.b/port=9090"}},
+		{expr: "servers.**.port", want: []string{"servers.a/port=8080", "servers.a.tls/port=8443", "servers.b/port=9090"}},
+		{expr: "servers.a.*", want: []string{"servers.a/port=8080", "servers.a/host=a.local"}},
+		{expr: "[*]/timeout", want: []string{"DEFAULT/timeout=10", "db.primary/timeout=30", "dbcache/timeout=5"}},
+		{expr: "[servers.?]/port", want: []string{"servers.a/port=8080", "servers.b/port=9090"}},
+		{expr: `section[name~="^db"]/timeout`, want: []string{"db.primary/timeout=30", "dbcache/timeout=5"}},
+		{expr: `section[name~="^db"][name!="dbcache"]/*`, want: []string{"db.primary/timeout=30", "db.primary/dsn=postgres://db/main"}},
+		{expr: `[db.primary]/dsn[value~="^postgres:"]`, want: []string{"db.primary/dsn=postgres://db/main"}},
+		{expr: `[*]/timeout[value="30"]`, want: []string{"db.primary/timeout=30"}},
+		{expr: `[*]/p*[name="port"][value!="80"]`, want: []string{"servers.a/port=8080", "servers.a.tls/port=8443", "servers.b/port=9090"}},
+		{expr: "servers.b.port[1]", want: []string{"servers.b/port=9091"}},
+		{expr: "servers.b.port[-1]", want: []string{"servers.b/port=9092"}},
+		{expr: "servers.b.port[5]", want: nil},
+		{expr: "servers.b.port[*]", want: []string{"servers.b/port=9090", "servers.b/port=9091", "servers.b/port=9092"}},
+		{expr: `servers.b.port[*][value!="9091"]`, want: []string{"servers.b/port=9090", "servers.b/port=9092"}},
+		{expr: "missing.key", want: nil},
+	}
+	for _, test := range tests {
+		t.Run(test.expr, func(t *testing.T) {
+			got := queryValues(t, test.expr)
+			if len(test.want) == 0 {
+				assert.Empty(t, got)
+				return
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		for _, expr := range []string{
+			"",
+			"/key",
+			"[servers]/",
+			"[servers/port",
+			`section[value="1"]/port`,
+			`section[name~="("]/port`,
+			"[servers]/port[x]",
+			"[servers]x/port",
+		} {
+			_, err := f.Query(expr)
+			assert.Error(t, err, expr)
+		}
+	})
+
+	t.Run("section named section", func(t *testing.T) {
+		f, err := Load([]byte(`
+[section]
+port = 1
+[other]
+port = 2
+`))
+		require.NoError(t, err)
+
+		for _, expr := range []string{"section/port", "section.port", "[section]/port"} {
+			keys, err := f.Query(expr)
+			require.NoError(t, err)
+			require.Len(t, keys, 1, expr)
+			assert.Equal(t, "1", keys[0].String())
+		}
+
+		keys, err := f.Query(`section[name!="DEFAULT"]/port`)
+		require.NoError(t, err)
+		assert.Len(t, keys, 2)
+	})
+
+	t.Run("insensitive", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{Insensitive: true}, []byte(`
+[Servers.A]
+Port = 80
+`))
+		require.NoError(t, err)
+
+		keys, err := f.Query("SERVERS.*.PORT")
+		require.NoError(t, err)
+		require.Len(t, keys, 1)
+		assert.Equal(t, "80", keys[0].String())
+	})
+}
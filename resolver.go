@@ -0,0 +1,124 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"context"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+// Resolver resolves the value of references in the form of "${scheme:argument}",
+// e.g. "${file:/run/secrets/db}", when ExtendedInterpolation is enabled.
+//
+// A resolver should return an error satisfying errors.Is(err, os.ErrNotExist)
+// when the value does not exist, so that the default value of the reference
+// (e.g. "${file:/run/secrets/db:-secret}") is used instead.
+type Resolver interface {
+	Resolve(ctx context.Context, arg string) (string, error)
+}
+
+// ResolverFunc is an adapter to allow the use of ordinary functions as resolvers.
+type ResolverFunc func(ctx context.Context, arg string) (string, error)
+
+// Resolve calls fn(ctx, arg).
+func (fn ResolverFunc) Resolve(ctx context.Context, arg string) (string, error) {
+	return fn(ctx, arg)
+}
+
+// Built-in resolvers, which are only used when set by LoadOptions.Resolvers, e.g.
+// map[string]Resolver{"env": EnvResolver}, because they give configuration files
+// access to the host.
+var (
+	// EnvResolver resolves the value of the environment variable.
+	EnvResolver Resolver = ResolverFunc(resolveEnv)
+	// FileResolver resolves the content of the file with trailing newlines trimmed.
+	FileResolver Resolver = ResolverFunc(resolveFile)
+	// Base64Resolver resolves the standard base64 decoded argument.
+	Base64Resolver Resolver = ResolverFunc(resolveBase64)
+)
+
+func resolveEnv(_ context.Context, name string) (string, error) {
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return "", os.ErrNotExist
+	}
+	return val, nil
+}
+
+func resolveFile(_ context.Context, name string) (string, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func resolveBase64(_ context.Context, arg string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(arg)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// resolver returns the resolver of given scheme, or nil if there is none.
+func (f *File) resolver(scheme string) Resolver {
+	return f.options.Resolvers[scheme]
+}
+
+// resolveReference resolves the argument with the resolver of given scheme
+// and caches the result unless the resolver cache is disabled.
+func (f *File) resolveReference(ctx context.Context, r Resolver, scheme, arg string) (string, error) {
+	cacheKey := scheme + ":" + arg
+	if !f.options.DisableResolverCache {
+		f.resolverCacheLock.RLock()
+		val, ok := f.resolverCache[cacheKey]
+		f.resolverCacheLock.RUnlock()
+		if ok {
+			return val, nil
+		}
+	}
+
+	if f.options.ResolverTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.options.ResolverTimeout)
+		defer cancel()
+	}
+	val, err := r.Resolve(ctx, arg)
+	if err != nil {
+		return "", err
+	}
+
+	if !f.options.DisableResolverCache {
+		f.resolverCacheLock.Lock()
+		if f.resolverCache == nil {
+			f.resolverCache = make(map[string]string)
+		}
+		f.resolverCache[cacheKey] = val
+		f.resolverCacheLock.Unlock()
+	}
+	return val, nil
+}
+
+// ClearResolverCache discards all cached values returned by resolvers, so that
+// they are resolved again on next access.
+func (f *File) ClearResolverCache() {
+	f.resolverCacheLock.Lock()
+	f.resolverCache = nil
+	f.resolverCacheLock.Unlock()
+}
@@ -0,0 +1,195 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver(t *testing.T) {
+	t.Run("built-in resolvers", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{
+			ExtendedInterpolation: true,
+			Resolvers: map[string]Resolver{
+				"env":    EnvResolver,
+				"file":   FileResolver,
+				"base64": Base64Resolver,
+			},
+		}, []byte(`
+[database]
+password = ${file:testdata/secret.txt}
+missing = ${file:testdata/404.txt:-default}
+no_default = ${file:testdata/404.txt}
+token = ${base64:aGVsbG8gd29ybGQ=}
+invalid = ${base64:!!!}
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		sec := f.Section("database")
+		assert.Equal(t, "s3cr3t", sec.Key("password").String())
+		assert.Equal(t, "default", sec.Key("missing").String())
+		assert.Equal(t, "hello world", sec.Key("token").String())
+		assert.Equal(t, "${base64:!!!}", sec.Key("invalid").String())
+
+		_, err = sec.Key("invalid").Resolve()
+		require.Error(t, err)
+		assert.True(t, IsErrResolveReference(err))
+		assert.Equal(t, "base64:!!!", err.(ErrResolveReference).Reference)
+
+		// Missing files without default values are errors rather than unresolved references.
+		assert.Equal(t, "${file:testdata/404.txt}", sec.Key("no_default").String())
+		_, err = sec.Key("no_default").Resolve()
+		require.Error(t, err)
+		assert.True(t, IsErrResolveReference(err))
+	})
+
+	t.Run("no resolvers by default", func(t *testing.T) {
+		for _, opts := range []LoadOptions{{ExtendedInterpolation: true}, PythonLoadOptions()} {
+			f, err := LoadSources(opts, []byte(`
+[section]
+host = ${env:HOME}
+secret = ${file:testdata/secret.txt}
+token = ${base64:aGVsbG8gd29ybGQ=}
+`))
+			require.NoError(t, err)
+			require.NotNil(t, f)
+
+			sec := f.Section("section")
+			assert.Equal(t, "${env:HOME}", sec.Key("host").String())
+			assert.Equal(t, "${file:testdata/secret.txt}", sec.Key("secret").String())
+			assert.Equal(t, "${base64:aGVsbG8gd29ybGQ=}", sec.Key("token").String())
+		}
+	})
+
+	t.Run("custom resolvers with cache", func(t *testing.T) {
+		calls := 0
+		vault := ResolverFunc(func(_ context.Context, arg string) (string, error) {
+			calls++
+			if arg == "broken" {
+				return "", errors.New("vault is sealed")
+			}
+			return "secret of " + arg, nil
+		})
+
+		f, err := LoadSources(LoadOptions{
+			ExtendedInterpolation: true,
+			Resolvers:             map[string]Resolver{"vault": vault},
+		}, []byte(`
+[app]
+password = ${vault:db}
+port = ${vault:broken}
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		key := f.Section("app").Key("password")
+		assert.Equal(t, "secret of db", key.String())
+		assert.Equal(t, "secret of db", key.String())
+		assert.Equal(t, 1, calls)
+
+		f.ClearResolverCache()
+		assert.Equal(t, "secret of db", key.String())
+		assert.Equal(t, 2, calls)
+
+		_, err = f.Section("app").Key("port").Int()
+		require.Error(t, err)
+		assert.True(t, IsErrResolveReference(err))
+		assert.EqualError(t, err, `resolve reference "vault:broken" in key "app:port": vault is sealed`)
+	})
+
+	t.Run("disable cache", func(t *testing.T) {
+		calls := 0
+		counter := ResolverFunc(func(_ context.Context, arg string) (string, error) {
+			calls++
+			return arg, nil
+		})
+
+		f, err := LoadSources(LoadOptions{
+			ExtendedInterpolation: true,
+			Resolvers:             map[string]Resolver{"counter": counter},
+			DisableResolverCache:  true,
+		}, []byte(`key = ${counter:value}`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		assert.Equal(t, "value", f.Section("").Key("key").String())
+		assert.Equal(t, "value", f.Section("").Key("key").String())
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("context and timeout", func(t *testing.T) {
+		slow := ResolverFunc(func(ctx context.Context, _ string) (string, error) {
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(time.Second):
+				return "slow", nil
+			}
+		})
+
+		f, err := LoadSources(LoadOptions{
+			ExtendedInterpolation: true,
+			Resolvers:             map[string]Resolver{"slow": slow},
+			ResolverTimeout:       10 * time.Millisecond,
+		}, []byte(`key = ${slow:value}`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		_, err = f.Section("").Key("key").Resolve()
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = f.Section("").Key("key").ResolveContext(ctx)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+	})
+
+	t.Run("sections take precedence over resolvers", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{
+			ExtendedInterpolation: true,
+			Resolvers: map[string]Resolver{
+				"env":  EnvResolver,
+				"file": FileResolver,
+			},
+		}, []byte(`
+[file]
+name = from section
+
+[env]
+HOME = /home/section
+
+[section]
+name = ${file:name}
+home = ${env:HOME}
+missing = ${file:testdata/secret.txt}
+`))
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		sec := f.Section("section")
+		assert.Equal(t, "from section", sec.Key("name").String())
+		assert.Equal(t, "/home/section", sec.Key("home").String())
+		assert.Equal(t, "${file:testdata/secret.txt}", sec.Key("missing").String())
+	})
+}
@@ -0,0 +1,283 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SaveOptions contains all customized options used for saving files.
+type SaveOptions struct {
+	// Perm is the permission bits of newly created files before umask. By default, it is 0666.
+	// Existing files always keep their mode and ownership.
+	Perm os.FileMode
+	// Backups is the number of backups of the existing file to keep. When it is 1, the
+	// previous content is kept in "<filename>.bak". When it is greater than 1, backups are
+	// rotated as "<filename>.bak.1" (the newest) to "<filename>.bak.<Backups>" (the oldest).
+	Backups int
+	// NoSync indicates whether to skip flushing the file and its directory to stable storage,
+	// which trades durability on crash for speed.
+	NoSync bool
+	// Write is the options of writing content. By default, it is DefaultWriteOptions().
+	Write *WriteOptions
+}
+
+// SaveToWithOptions writes content to file system atomically with given options,
+// i.e. the file either has the previous or the new content even on crash.
+//
+// Content is written to a temporary file in the same directory and renamed to
+// the filename. When the filename is a symbolic link, its target is replaced
+// and the link is kept.
+func (f *File) SaveToWithOptions(filename string, opts SaveOptions) error {
+	buf, err := f.writeToBufferWith(opts.Write)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filename, buf.Bytes(), opts)
+}
+
+// writeToBufferWith writes content with given write options, or the default ones when nil.
+func (f *File) writeToBufferWith(opts *WriteOptions) (*bytes.Buffer, error) {
+	if opts == nil {
+		return f.writeToBuffer("")
+	}
+	return f.writeToBufferWithOptions(*opts)
+}
+
+// sourceFileOf returns the data source of given file, or nil if there is none.
+func (f *File) sourceFileOf(filename string) *sourceFile {
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil
+	}
+
+	if f.BlockMode {
+		f.lock.RLock()
+		defer f.lock.RUnlock()
+	}
+	for _, s := range f.dataSources {
+		src, ok := s.(*sourceFile)
+		if !ok {
+			continue
+		}
+		if name, err := filepath.Abs(src.name); err == nil && name == abs {
+			return src
+		}
+	}
+	return nil
+}
+
+// SaveIfUnchanged is like SaveToWithOptions but returns ErrConflict without saving
+// when the file has been changed by others since it was loaded or last saved by
+// this method. The file must be one of the data sources of the file.
+//
+// To prevent changes made by other processes between the check and the save,
+// coordinate with them using LockFile.
+func (f *File) SaveIfUnchanged(filename string, opts ...SaveOptions) error {
+	var opt SaveOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	src := f.sourceFileOf(filename)
+	if src == nil {
+		return fmt.Errorf("file %q is not a data source", filename)
+	}
+
+	sum, exists, err := fileChecksum(filename)
+	if err != nil {
+		return err
+	}
+	if f.BlockMode {
+		f.lock.RLock()
+	}
+	changed := exists != src.exists || (exists && sum != src.checksum)
+	if f.BlockMode {
+		f.lock.RUnlock()
+	}
+	if changed {
+		return ErrConflict{Filename: filename}
+	}
+
+	buf, err := f.writeToBufferWith(opt.Write)
+	if err != nil {
+		return err
+	}
+	if err = writeFileAtomic(filename, buf.Bytes(), opt); err != nil {
+		return err
+	}
+
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+	src.exists = true
+	src.checksum = sha256.Sum256(buf.Bytes())
+	return nil
+}
+
+// backupName returns the name of the n-th backup of given file.
+func backupName(filename string, n, backups int) string {
+	if backups == 1 {
+		return filename + ".bak"
+	}
+	return fmt.Sprintf("%s.bak.%d", filename, n)
+}
+
+// rotateBackups rotates existing backups of given file and backs up the current content.
+func rotateBackups(filename string, backups int) error {
+	if backups > 1 {
+		if err := os.Remove(backupName(filename, backups, backups)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		for n := backups - 1; n >= 1; n-- {
+			err := os.Rename(backupName(filename, n, backups), backupName(filename, n+1, backups))
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	}
+
+	backup := backupName(filename, 1, backups)
+	if err := os.Remove(backup); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	// Hard link is cheap and keeps the original file as-is after the rename,
+	// fall back to copy when it is not supported by the file system.
+	if os.Link(filename, backup) == nil {
+		return nil
+	}
+	return copyFile(filename, backup)
+}
+
+// copyFile copies content and permission bits of src to dst.
+func copyFile(src, dst string) (err error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fi.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := out.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// createTempFile creates a new file with unique name in given directory.
+func createTempFile(dir, base string, perm os.FileMode) (*os.File, error) {
+	for i := 0; i < 100; i++ {
+		var suffix [8]byte
+		if _, err := rand.Read(suffix[:]); err != nil {
+			return nil, err
+		}
+
+		name := filepath.Join(dir, "."+base+".tmp-"+hex.EncodeToString(suffix[:]))
+		f, err := os.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+		if os.IsExist(err) {
+			continue
+		}
+		return f, err
+	}
+	return nil, fmt.Errorf("failed to create temporary file for %q", base)
+}
+
+// writeFileAtomic writes data to the file by renaming a temporary file to it.
+func writeFileAtomic(filename string, data []byte, opts SaveOptions) (err error) {
+	if opts.Perm == 0 {
+		opts.Perm = 0666
+	}
+
+	// Replace the target instead of the symbolic link itself.
+	if fi, err := os.Lstat(filename); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		target, err := filepath.EvalSymlinks(filename)
+		if err != nil {
+			return err
+		}
+		filename = target
+	}
+
+	existing, err := os.Stat(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	tmp, err := createTempFile(filepath.Dir(filename), filepath.Base(filename), opts.Perm)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if existing != nil {
+		mode := existing.Mode() & (os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky)
+		if err = tmp.Chmod(mode); err != nil {
+			return err
+		}
+		if err = chownLike(tmp, existing); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if !opts.NoSync {
+		if err = tmp.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if existing != nil && opts.Backups > 0 {
+		if err = rotateBackups(filename, opts.Backups); err != nil {
+			return err
+		}
+	}
+
+	if err = os.Rename(tmp.Name(), filename); err != nil {
+		return err
+	}
+	if opts.NoSync {
+		return nil
+	}
+	return syncDir(filepath.Dir(filename))
+}
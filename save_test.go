@@ -0,0 +1,232 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readFile(t *testing.T, filename string) string {
+	data, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestFile_SaveToWithOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ini")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	t.Run("create and replace", func(t *testing.T) {
+		filename := filepath.Join(dir, "create.ini")
+
+		f := Empty()
+		f.Section("").Key("name").SetValue("first")
+		require.NoError(t, f.SaveToWithOptions(filename, SaveOptions{}))
+		assert.Equal(t, "name = first\n", readFile(t, filename))
+
+		f.Section("").Key("name").SetValue("second")
+		require.NoError(t, f.SaveTo(filename))
+		assert.Equal(t, "name = second\n", readFile(t, filename))
+
+		// No temporary files are left behind.
+		matches, err := filepath.Glob(filepath.Join(dir, ".create.ini.tmp-*"))
+		require.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("preserve mode", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Skipping file mode test on Windows")
+		}
+
+		filename := filepath.Join(dir, "mode.ini")
+		require.NoError(t, ioutil.WriteFile(filename, []byte("name = old\n"), 0600))
+		require.NoError(t, os.Chmod(filename, 0640))
+
+		f := Empty()
+		f.Section("").Key("name").SetValue("new")
+		require.NoError(t, f.SaveToWithOptions(filename, SaveOptions{Perm: 0644}))
+
+		fi, err := os.Stat(filename)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0640), fi.Mode().Perm())
+		assert.Equal(t, "name = new\n", readFile(t, filename))
+	})
+
+	t.Run("preserve symbolic link", func(t *testing.T) {
+		if runtime.GOOS == "windows" {
+			t.Skip("Skipping symbolic link test on Windows")
+		}
+
+		target := filepath.Join(dir, "target.ini")
+		link := filepath.Join(dir, "link.ini")
+		require.NoError(t, ioutil.WriteFile(target, []byte("name = old\n"), 0600))
+		require.NoError(t, os.Symlink(target, link))
+
+		f := Empty()
+		f.Section("").Key("name").SetValue("new")
+		require.NoError(t, f.SaveTo(link))
+
+		fi, err := os.Lstat(link)
+		require.NoError(t, err)
+		assert.True(t, fi.Mode()&os.ModeSymlink != 0)
+		assert.Equal(t, "name = new\n", readFile(t, target))
+	})
+
+	t.Run("single backup", func(t *testing.T) {
+		filename := filepath.Join(dir, "backup.ini")
+
+		f := Empty()
+		for _, val := range []string{"1", "2", "3"} {
+			f.Section("").Key("version").SetValue(val)
+			require.NoError(t, f.SaveToWithOptions(filename, SaveOptions{Backups: 1}))
+		}
+		assert.Equal(t, "version = 3\n", readFile(t, filename))
+		assert.Equal(t, "version = 2\n", readFile(t, filename+".bak"))
+	})
+
+	t.Run("numbered backups", func(t *testing.T) {
+		filename := filepath.Join(dir, "numbered.ini")
+
+		f := Empty()
+		for _, val := range []string{"1", "2", "3", "4", "5"} {
+			f.Section("").Key("version").SetValue(val)
+			require.NoError(t, f.SaveToWithOptions(filename, SaveOptions{Backups: 3, NoSync: true}))
+		}
+		assert.Equal(t, "version = 5\n", readFile(t, filename))
+		assert.Equal(t, "version = 4\n", readFile(t, filename+".bak.1"))
+		assert.Equal(t, "version = 3\n", readFile(t, filename+".bak.2"))
+		assert.Equal(t, "version = 2\n", readFile(t, filename+".bak.3"))
+
+		_, err := os.Stat(filename + ".bak.4")
+		assert.True(t, os.IsNotExist(err))
+	})
+}
+
+func TestFile_SaveIfUnchanged(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ini")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	filename := filepath.Join(dir, "app.ini")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("name = old\n"), 0666))
+
+	f, err := Load(filename)
+	require.NoError(t, err)
+	require.NotNil(t, f)
+
+	f.Section("").Key("name").SetValue("new")
+	require.NoError(t, f.SaveIfUnchanged(filename))
+	assert.Equal(t, "name = new\n", readFile(t, filename))
+
+	// Saved content is recorded so subsequent saves succeed.
+	f.Section("").Key("name").SetValue("newer")
+	require.NoError(t, f.SaveIfUnchanged(filename))
+
+	t.Run("changed by others", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(filename, []byte("name = other\n"), 0666))
+
+		f.Section("").Key("name").SetValue("mine")
+		err := f.SaveIfUnchanged(filename)
+		require.Error(t, err)
+		assert.True(t, IsErrConflict(err))
+		assert.Equal(t, "name = other\n", readFile(t, filename))
+
+		// Reloading picks up the change and allows saving again.
+		require.NoError(t, f.Reload())
+		require.NoError(t, f.SaveIfUnchanged(filename))
+	})
+
+	t.Run("created by others", func(t *testing.T) {
+		missing := filepath.Join(dir, "missing.ini")
+		f, err := LooseLoad(missing)
+		require.NoError(t, err)
+		require.NotNil(t, f)
+
+		require.NoError(t, ioutil.WriteFile(missing, []byte("name = other\n"), 0666))
+		assert.True(t, IsErrConflict(f.SaveIfUnchanged(missing)))
+
+		require.NoError(t, os.Remove(missing))
+		require.NoError(t, f.SaveIfUnchanged(missing))
+	})
+
+	t.Run("not a data source", func(t *testing.T) {
+		require.Error(t, f.SaveIfUnchanged(filepath.Join(dir, "other.ini")))
+	})
+
+	t.Run("concurrent reloads", func(t *testing.T) {
+		require.NoError(t, f.Reload())
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 20; i++ {
+				_ = f.Reload()
+			}
+		}()
+		for i := 0; i < 20; i++ {
+			require.NoError(t, f.SaveIfUnchanged(filename))
+		}
+		<-done
+	})
+}
+
+func TestLockFile(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "darwin" {
+		t.Skip("Skipping file locking test on " + runtime.GOOS)
+	}
+
+	dir, err := ioutil.TempDir("", "ini")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	filename := filepath.Join(dir, "app.ini")
+	lock, err := LockFile(filename)
+	require.NoError(t, err)
+
+	acquired := make(chan *FileLock)
+	go func() {
+		lock, err := LockFile(filename)
+		if err != nil {
+			close(acquired)
+			return
+		}
+		acquired <- lock
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("lock acquired while held by others")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	require.NoError(t, lock.Unlock())
+	select {
+	case lock := <-acquired:
+		require.NotNil(t, lock)
+		require.NoError(t, lock.Unlock())
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock not acquired after released")
+	}
+}
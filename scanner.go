@@ -0,0 +1,304 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// TokenType is the type of tokens produced by Scanner.
+type TokenType int
+
+const (
+	// TokenSectionStart is a section header, Name is the section name.
+	TokenSectionStart TokenType = iota + 1
+	// TokenKey is a key, Name is the key name and Value is the parsed value.
+	// Keys appearing more than once produce one token for each occurrence.
+	TokenKey
+	// TokenNestedValue is a nested value of the last key, see LoadOptions.AllowNestedValues.
+	TokenNestedValue
+	// TokenComment is a comment line, Value is the line including the comment prefix.
+	TokenComment
+	// TokenBlank is an empty line.
+	TokenBlank
+	// TokenRawLine is a line of unparseable sections, Value is the line with
+	// leading whitespace removed but keeping the line break.
+	TokenRawLine
+)
+
+var tokenTypeNames = map[TokenType]string{
+	TokenSectionStart: "SectionStart",
+	TokenKey:          "Key",
+	TokenNestedValue:  "NestedValue",
+	TokenComment:      "Comment",
+	TokenBlank:        "Blank",
+	TokenRawLine:      "RawLine",
+}
+
+func (t TokenType) String() string {
+	if name, ok := tokenTypeNames[t]; ok {
+		return name
+	}
+	return "TokenType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Token is a syntactic element of INI data produced by Scanner.
+type Token struct {
+	Type TokenType
+	// Line is the 1-based line number where the token starts.
+	Line int
+
+	Name          string
+	Value         string
+	InlineComment string
+	// IsAutoIncrement indicates whether the key name is generated from "-".
+	IsAutoIncrement bool
+	// IsBoolean indicates whether the key has no value, see LoadOptions.AllowBooleanKeys.
+	IsBoolean bool
+}
+
+// Scanner reads INI data as a stream of tokens without building a File, which
+// allows processing data larger than memory. Options affecting the syntax are
+// respected, while ones affecting the in-memory model (e.g. Insensitive,
+// AllowShadows) are left to the caller.
+//
+// Scanning stops at the first error, which is returned by Err.
+type Scanner struct {
+	p       *parser
+	options LoadOptions
+
+	started    bool
+	bufferSize int
+	token      Token
+	err        error
+
+	inUnparseableSection bool
+	isLastValueEmpty     bool
+}
+
+// NewScanner returns a new scanner reading from r with given load options.
+func NewScanner(r io.Reader, opts LoadOptions) *Scanner {
+	if len(opts.KeyValueDelimiters) == 0 {
+		opts.KeyValueDelimiters = "=:"
+	}
+
+	return &Scanner{
+		p: newParser(r, parserOptions{
+			IgnoreContinuation:          opts.IgnoreContinuation,
+			IgnoreInlineComment:         opts.IgnoreInlineComment,
+			AllowPythonMultilineValues:  opts.AllowPythonMultilineValues,
+			SpaceBeforeInlineComment:    opts.SpaceBeforeInlineComment,
+			UnescapeValueDoubleQuotes:   opts.UnescapeValueDoubleQuotes,
+			UnescapeValueCommentSymbols: opts.UnescapeValueCommentSymbols,
+			PreserveSurroundedQuote:     opts.PreserveSurroundedQuote,
+			StrictPythonMultilineValues: opts.StrictPythonMultilineValues,
+			EmptyLinesInValues:          opts.EmptyLinesInValues,
+			DebugFunc:                   opts.DebugFunc,
+			ReaderBufferSize:            opts.ReaderBufferSize,
+		}),
+		options: opts,
+	}
+}
+
+// Token returns the most recent token produced by Scan.
+func (s *Scanner) Token() Token {
+	return s.token
+}
+
+// Err returns the first error encountered by the scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// start handles the BOM and determines the peek size for multi-line values.
+func (s *Scanner) start() error {
+	if err := s.p.BOM(); err != nil {
+		return fmt.Errorf("BOM: %v", err)
+	}
+
+	if s.options.AllowPythonMultilineValues {
+		// NOTE: Iterate and increase currentPeekSize until
+		// the size of the parser buffer is found.
+		// TODO(unknwon): When Golang 1.10 is the lowest version supported, replace with `parserBufferSize := p.buf.Size()`.
+		// NOTE: Peek 4kb at a time.
+		currentPeekSize := minReaderBufferSize
+		for {
+			peekBytes, _ := s.p.buf.Peek(currentPeekSize)
+			peekBytesLength := len(peekBytes)
+
+			if s.bufferSize >= peekBytesLength {
+				break
+			}
+
+			currentPeekSize *= 2
+			s.bufferSize = peekBytesLength
+		}
+	}
+	return nil
+}
+
+// Scan advances the scanner to the next token, which is then available through
+// Token. It returns false when reaching the end of input or an error.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	if !s.started {
+		s.started = true
+		if s.err = s.start(); s.err != nil {
+			return false
+		}
+	}
+
+	for !s.p.isEOF {
+		tok, ok, err := s.next()
+		if err != nil {
+			s.err = err
+			return false
+		} else if ok {
+			s.token = tok
+			return true
+		}
+	}
+	return false
+}
+
+// next reads the next line and returns the token, or false when the line
+// produces no token.
+func (s *Scanner) next() (Token, bool, error) {
+	p := s.p
+	line, err := p.readUntil('\n')
+	if err != nil {
+		return Token{}, false, err
+	} else if len(line) == 0 {
+		return Token{}, false, nil
+	}
+	tok := Token{Line: p.lineNum}
+
+	if s.options.AllowNestedValues && s.isLastValueEmpty {
+		if line[0] == ' ' || line[0] == '\t' {
+			tok.Type = TokenNestedValue
+			tok.Value = string(bytes.TrimSpace(line))
+			return tok, true, nil
+		}
+	}
+
+	indent := len(line)
+	line = bytes.TrimLeftFunc(line, unicode.IsSpace)
+	if len(line) == 0 {
+		tok.Type = TokenBlank
+		return tok, true, nil
+	}
+	p.indent = indent - len(line)
+
+	// Comments
+	if line[0] == '#' || line[0] == ';' {
+		tok.Type = TokenComment
+		tok.Value = string(bytes.TrimRight(line, "\r\n"))
+		return tok, true, nil
+	}
+
+	// Section
+	if line[0] == '[' {
+		// Read to the next ']' (TODO: support quoted strings)
+		closeIdx := bytes.LastIndexByte(line, ']')
+		if closeIdx == -1 {
+			return tok, false, fmt.Errorf("unclosed section: %s", line)
+		}
+
+		tok.Type = TokenSectionStart
+		tok.Name = string(line[1:closeIdx])
+		if comment, has := cleanComment(line[closeIdx+1:]); has {
+			tok.InlineComment = string(bytes.TrimSpace(comment))
+		}
+
+		// Reset auto-counter
+		p.count = 1
+		// Nested values can't span sections
+		s.isLastValueEmpty = false
+
+		s.inUnparseableSection = false
+		for i := range s.options.UnparseableSections {
+			if s.options.UnparseableSections[i] == tok.Name ||
+				((s.options.Insensitive || s.options.InsensitiveSections) && strings.EqualFold(s.options.UnparseableSections[i], tok.Name)) {
+				s.inUnparseableSection = true
+				break
+			}
+		}
+		return tok, true, nil
+	}
+
+	if s.inUnparseableSection {
+		tok.Type = TokenRawLine
+		tok.Value = string(line)
+		return tok, true, nil
+	}
+
+	// Convert the line only once, the key name, value and inline comment
+	// are substrings of it.
+	text := string(line)
+	kname, offset, err := readKeyName(s.options.KeyValueDelimiters, text)
+	if err != nil {
+		switch {
+		// Treat as boolean key when desired, and whole line is key name.
+		case IsErrDelimiterNotFound(err):
+			switch {
+			case s.options.AllowBooleanKeys:
+				kname, err := p.readValue(text, s.bufferSize)
+				if err != nil {
+					return tok, false, err
+				}
+				tok.Type = TokenKey
+				tok.Name = kname
+				tok.Value = "true"
+				tok.IsBoolean = true
+				tok.InlineComment = p.inlineComment
+				p.inlineComment = ""
+				return tok, true, nil
+
+			case s.options.SkipUnrecognizableLines:
+				return tok, false, nil
+			}
+		case IsErrEmptyKeyName(err) && s.options.SkipUnrecognizableLines:
+			return tok, false, nil
+		}
+		return tok, false, err
+	}
+
+	// Auto increment.
+	if kname == "-" {
+		tok.IsAutoIncrement = true
+		kname = "#" + strconv.Itoa(p.count)
+		p.count++
+	}
+
+	value, err := p.readValue(text[offset:], s.bufferSize)
+	if err != nil {
+		return tok, false, err
+	}
+	s.isLastValueEmpty = len(value) == 0
+
+	tok.Type = TokenKey
+	tok.Name = kname
+	tok.Value = value
+	tok.InlineComment = p.inlineComment
+	p.inlineComment = ""
+	return tok, true, nil
+}
@@ -0,0 +1,109 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scanAll(t *testing.T, data string, opts LoadOptions) ([]Token, error) {
+	s := NewScanner(strings.NewReader(data), opts)
+	var tokens []Token
+	for s.Scan() {
+		tokens = append(tokens, s.Token())
+	}
+	return tokens, s.Err()
+}
+
+func TestScanner(t *testing.T) {
+	t.Run("tokens with positions", func(t *testing.T) {
+		tokens, err := scanAll(t, `; Leading comment
+name = app
+
+[server] # Inline
+host = localhost ; Port follows
+- = first
+- = second
+address = """multi
+line"""
+port = 8080
+
+[raw]
+anything goes
+`, LoadOptions{UnparseableSections: []string{"raw"}})
+		require.NoError(t, err)
+		assert.Equal(t, []Token{
+			{Type: TokenComment, Line: 1, Value: "; Leading comment"},
+			{Type: TokenKey, Line: 2, Name: "name", Value: "app"},
+			{Type: TokenBlank, Line: 3},
+			{Type: TokenSectionStart, Line: 4, Name: "server", InlineComment: "# Inline"},
+			{Type: TokenKey, Line: 5, Name: "host", Value: "localhost", InlineComment: "; Port follows"},
+			{Type: TokenKey, Line: 6, Name: "#1", Value: "first", IsAutoIncrement: true},
+			{Type: TokenKey, Line: 7, Name: "#2", Value: "second", IsAutoIncrement: true},
+			{Type: TokenKey, Line: 8, Name: "address", Value: "multi\nline"},
+			{Type: TokenKey, Line: 10, Name: "port", Value: "8080"},
+			{Type: TokenBlank, Line: 11},
+			{Type: TokenSectionStart, Line: 12, Name: "raw"},
+			{Type: TokenRawLine, Line: 13, Value: "anything goes\n"},
+		}, tokens)
+	})
+
+	t.Run("boolean keys and nested values", func(t *testing.T) {
+		tokens, err := scanAll(t, `[section]
+enabled
+key =
+  nested
+last = z
+`, LoadOptions{
+			AllowBooleanKeys:  true,
+			AllowNestedValues: true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []Token{
+			{Type: TokenSectionStart, Line: 1, Name: "section"},
+			{Type: TokenKey, Line: 2, Name: "enabled", Value: "true", IsBoolean: true},
+			{Type: TokenKey, Line: 3, Name: "key"},
+			{Type: TokenNestedValue, Line: 4, Value: "nested"},
+			{Type: TokenKey, Line: 5, Name: "last", Value: "z"},
+		}, tokens)
+	})
+
+	t.Run("Python multi-line values", func(t *testing.T) {
+		tokens, err := scanAll(t, `list = a
+  b
+
+  c
+last = z
+`, PythonLoadOptions())
+		require.NoError(t, err)
+		assert.Equal(t, []Token{
+			{Type: TokenKey, Line: 1, Name: "list", Value: "a\nb\n\nc"},
+			{Type: TokenKey, Line: 5, Name: "last", Value: "z"},
+		}, tokens)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		tokens, err := scanAll(t, "name = app\n[unclosed\nkey = value\n", LoadOptions{})
+		require.Error(t, err)
+		assert.Len(t, tokens, 1)
+	})
+
+	assert.Equal(t, "SectionStart", TokenSectionStart.String())
+	assert.Equal(t, "TokenType(0)", TokenType(0).String())
+}
@@ -0,0 +1,322 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+const (
+	secretPrefix    = "ENC["
+	secretSuffix    = "]"
+	secretAlgorithm = "AES256_GCM"
+	// Length of AES-256 keys in bytes.
+	secretKeyLength = 32
+)
+
+// Keyring holds named AES-256 keys used to encrypt and decrypt secret values.
+// Values are always encrypted with the primary key, and decrypted with the key
+// they were encrypted with, which allows rotating keys with File.Reencrypt.
+type Keyring struct {
+	lock    sync.RWMutex
+	keys    map[string]cipher.AEAD
+	primary string
+}
+
+// NewKeyring returns a new keyring with given 32-byte key as the primary key.
+func NewKeyring(id string, key []byte) (*Keyring, error) {
+	kr := &Keyring{
+		keys: make(map[string]cipher.AEAD),
+	}
+	if err := kr.Add(id, key); err != nil {
+		return nil, err
+	}
+	kr.primary = id
+	return kr, nil
+}
+
+// Add adds given 32-byte key to the keyring, replacing the existing one with the same ID.
+func (kr *Keyring) Add(id string, key []byte) error {
+	if id == "" || strings.ContainsAny(id, ",:[]") {
+		return fmt.Errorf("invalid key ID %q", id)
+	}
+	if len(key) != secretKeyLength {
+		return fmt.Errorf("invalid key length %d, must be %d bytes", len(key), secretKeyLength)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+
+	kr.lock.Lock()
+	defer kr.lock.Unlock()
+	kr.keys[id] = aead
+	return nil
+}
+
+// SetPrimary sets the key with given ID to be used for encrypting values.
+func (kr *Keyring) SetPrimary(id string) error {
+	kr.lock.Lock()
+	defer kr.lock.Unlock()
+
+	if _, ok := kr.keys[id]; !ok {
+		return fmt.Errorf("key %q does not exist", id)
+	}
+	kr.primary = id
+	return nil
+}
+
+// Primary returns the ID of the primary key.
+func (kr *Keyring) Primary() string {
+	kr.lock.RLock()
+	defer kr.lock.RUnlock()
+	return kr.primary
+}
+
+// encrypt returns the encrypted value of plaintext in the form of
+// "ENC[AES256_GCM,data:...,iv:...,tag:...,kid:...]" using the primary key.
+// The value can only be decrypted with the same additional data.
+func (kr *Keyring) encrypt(plaintext string, aad []byte) (string, error) {
+	kr.lock.RLock()
+	id := kr.primary
+	aead := kr.keys[id]
+	kr.lock.RUnlock()
+
+	iv := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nil, iv, []byte(plaintext), aad)
+	data, tag := sealed[:len(sealed)-aead.Overhead()], sealed[len(sealed)-aead.Overhead():]
+
+	enc := base64.StdEncoding
+	return fmt.Sprintf("%s%s,data:%s,iv:%s,tag:%s,kid:%s%s",
+		secretPrefix, secretAlgorithm,
+		enc.EncodeToString(data), enc.EncodeToString(iv), enc.EncodeToString(tag), id,
+		secretSuffix), nil
+}
+
+// decrypt returns the plaintext of given encrypted value and additional data.
+func (kr *Keyring) decrypt(val string, aad []byte) (string, error) {
+	if !isSecretValue(val) {
+		return "", errors.New("value is not encrypted")
+	}
+
+	fields := strings.Split(val[len(secretPrefix):len(val)-len(secretSuffix)], ",")
+	if fields[0] != secretAlgorithm {
+		return "", fmt.Errorf("unsupported encryption algorithm %q", fields[0])
+	}
+	parts := make(map[string]string, len(fields)-1)
+	for _, field := range fields[1:] {
+		i := strings.Index(field, ":")
+		if i == -1 {
+			return "", fmt.Errorf("malformed encrypted value field %q", field)
+		}
+		parts[field[:i]] = field[i+1:]
+	}
+
+	kr.lock.RLock()
+	aead, ok := kr.keys[parts["kid"]]
+	kr.lock.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("key %q does not exist", parts["kid"])
+	}
+
+	var data, iv, tag []byte
+	for name, dst := range map[string]*[]byte{"data": &data, "iv": &iv, "tag": &tag} {
+		b, err := base64.StdEncoding.DecodeString(parts[name])
+		if err != nil {
+			return "", fmt.Errorf("decode %s: %v", name, err)
+		}
+		*dst = b
+	}
+	if len(iv) != aead.NonceSize() {
+		return "", fmt.Errorf("invalid iv length %d", len(iv))
+	}
+
+	plaintext, err := aead.Open(nil, iv, append(data, tag...), aad)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// isSecretValue returns true if the value is in the form of encrypted values.
+func isSecretValue(val string) bool {
+	return strings.HasPrefix(val, secretPrefix+secretAlgorithm+",") && strings.HasSuffix(val, secretSuffix)
+}
+
+// SetKeyring attaches the keyring to the file for decrypting secret values
+// transparently. Passing nil detaches the keyring.
+func (f *File) SetKeyring(kr *Keyring) {
+	f.keyringLock.Lock()
+	defer f.keyringLock.Unlock()
+	f.keyring = kr
+}
+
+// Keyring returns the keyring attached to the file, or nil if there is none.
+func (f *File) Keyring() *Keyring {
+	f.keyringLock.RLock()
+	defer f.keyringLock.RUnlock()
+	return f.keyring
+}
+
+// Reencrypt re-encrypts all secret values of the file with the primary key of
+// the attached keyring, e.g. after a new primary key is set to rotate keys. It
+// also binds values renamed or copied from other keys while no keyring was
+// attached to their current keys, which is required before saving them.
+func (f *File) Reencrypt() error {
+	kr := f.Keyring()
+	if kr == nil {
+		return errors.New("no keyring attached")
+	}
+
+	for _, sec := range f.Sections() {
+		for _, key := range sec.Keys() {
+			if key.s != sec {
+				continue // Inherited from the default section
+			}
+			for _, k := range key.withShadows() {
+				old := k.Value()
+				if !isSecretValue(old) {
+					continue
+				}
+
+				plaintext, err := kr.decrypt(old, k.secretAAD())
+				if err != nil {
+					return fmt.Errorf("decrypt key %q: %v", keyPath(k), err)
+				}
+				k.secretPath = ""
+				val, err := kr.encrypt(plaintext, k.secretAAD())
+				if err != nil {
+					return fmt.Errorf("encrypt key %q: %v", keyPath(k), err)
+				}
+				k.SetValue(val)
+			}
+		}
+	}
+	return nil
+}
+
+// SetSecret encrypts plaintext with the primary key of given keyring and sets
+// the encrypted value to the key. The plaintext is never stored in the file.
+// The encrypted value is bound to the section and name of the key, so that it
+// cannot be decrypted when copied to other keys.
+func (k *Key) SetSecret(plaintext string, kr *Keyring) error {
+	if kr == nil {
+		return errors.New("no keyring given")
+	}
+
+	k = k.origin()
+	k.secretPath = ""
+	val, err := kr.encrypt(plaintext, k.secretAAD())
+	if err != nil {
+		return err
+	}
+	k.SetValue(val)
+	return nil
+}
+
+// pinSecret keeps encrypted values of the key and its shadows bound to the
+// current path of the key before it is renamed.
+func (k *Key) pinSecret() {
+	for _, key := range append([]*Key{k}, k.shadows...) {
+		key.secretPath = string(key.secretAAD())
+	}
+}
+
+// rebindSecret re-encrypts the value of the key pinned to another path after
+// it is renamed or copied, using the keyring attached to the file. The value
+// stays pinned if it cannot be re-encrypted, and the file refuses to write it.
+// The caller must hold the lock.
+func (k *Key) rebindSecret() {
+	if k.secretPath == "" {
+		return
+	}
+	path := k.s.name + ":" + k.name
+	if k.secretPath == path || !isSecretValue(k.value) {
+		k.secretPath = ""
+		return
+	}
+	kr := k.s.f.Keyring()
+	if kr == nil {
+		return
+	}
+
+	plaintext, err := kr.decrypt(k.value, []byte(k.secretPath))
+	if err != nil {
+		return
+	}
+	val, err := kr.encrypt(plaintext, []byte(path))
+	if err != nil {
+		return
+	}
+	k.setValue(val)
+}
+
+// secretAAD returns the additional data binding encrypted values to the key,
+// which is the path of the key its value is copied from if any.
+func (k *Key) secretAAD() []byte {
+	if k.secretPath != "" {
+		return []byte(k.secretPath)
+	}
+	return []byte(k.s.name + ":" + k.name)
+}
+
+// checkSecret returns an error if the encrypted value of the key is still
+// bound to another path, and would be impossible to decrypt once written.
+func (k *Key) checkSecret() error {
+	if k.secretPath == "" || !isSecretValue(k.value) {
+		return nil
+	}
+	return fmt.Errorf("encrypted value of key %q is bound to %q, attach a keyring to re-encrypt it", keyPath(k), k.secretPath)
+}
+
+// IsSecret returns true if the value of the key is encrypted.
+func (k *Key) IsSecret() bool {
+	return isSecretValue(k.Value())
+}
+
+// decryptSecret returns the plaintext of the encrypted value of the key and
+// true, or false if the value is not encrypted or no keyring is attached.
+func (k *Key) decryptSecret() (string, bool, error) {
+	val := k.Value()
+	if !isSecretValue(val) {
+		return "", false, nil
+	}
+	kr := k.s.f.Keyring()
+	if kr == nil {
+		return "", false, nil
+	}
+
+	plaintext, err := kr.decrypt(val, k.secretAAD())
+	if err != nil {
+		return "", true, fmt.Errorf("decrypt key %q: %v", keyPath(k), err)
+	}
+	return plaintext, true, nil
+}
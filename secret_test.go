@@ -0,0 +1,199 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKey_SetSecret(t *testing.T) {
+	kr, err := NewKeyring("k1", bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+	require.NotNil(t, kr)
+
+	f := Empty()
+	f.SetKeyring(kr)
+	key := f.Section("database").Key("password")
+	require.NoError(t, key.SetSecret("p@ssw0rd", kr))
+	f.Section("database").Key("port").SetValue("3306")
+
+	assert.True(t, key.IsSecret())
+	assert.False(t, f.Section("database").Key("port").IsSecret())
+	assert.True(t, strings.HasPrefix(key.Value(), "ENC[AES256_GCM,data:"))
+	assert.True(t, strings.HasSuffix(key.Value(), ",kid:k1]"))
+	assert.Equal(t, "p@ssw0rd", key.String())
+
+	val, err := key.Resolve()
+	require.NoError(t, err)
+	assert.Equal(t, "p@ssw0rd", val)
+
+	var buf bytes.Buffer
+	_, err = f.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.NotContains(t, buf.String(), "p@ssw0rd")
+
+	t.Run("load encrypted values", func(t *testing.T) {
+		f2, err := Load(buf.Bytes())
+		require.NoError(t, err)
+		require.NotNil(t, f2)
+
+		key := f2.Section("database").Key("password")
+		assert.Equal(t, f.Section("database").Key("password").Value(), key.Value())
+		assert.Equal(t, key.Value(), key.String())
+
+		f2.SetKeyring(kr)
+		assert.Equal(t, "p@ssw0rd", key.String())
+		assert.Equal(t, "3306", f2.Section("database").Key("port").String())
+	})
+
+	t.Run("wrong key", func(t *testing.T) {
+		other, err := NewKeyring("k1", bytes.Repeat([]byte{2}, 32))
+		require.NoError(t, err)
+
+		f2, err := Load(buf.Bytes())
+		require.NoError(t, err)
+		f2.SetKeyring(other)
+
+		key := f2.Section("database").Key("password")
+		assert.Equal(t, key.Value(), key.String())
+		_, err = key.Resolve()
+		require.Error(t, err)
+	})
+
+	t.Run("bound to the key", func(t *testing.T) {
+		f2, err := Load(buf.Bytes())
+		require.NoError(t, err)
+		f2.SetKeyring(kr)
+
+		// Copied values cannot be decrypted by other keys.
+		sec := f2.Section("database")
+		stolen := f2.Section("public").Key("password")
+		stolen.SetValue(sec.Key("password").Value())
+		assert.Equal(t, sec.Key("password").Value(), stolen.String())
+		_, err = stolen.Resolve()
+		require.Error(t, err)
+		other := sec.Key("other")
+		other.SetValue(sec.Key("password").Value())
+		_, err = other.Resolve()
+		require.Error(t, err)
+		f2.DeleteSection("public")
+		sec.DeleteKey("other")
+
+		// Renamed and copied keys are re-encrypted with the attached keyring.
+		require.NoError(t, sec.RenameKey("password", "secret"))
+		assert.Equal(t, "p@ssw0rd", sec.Key("secret").String())
+		copied, err := f2.CopySection(sec, "backup")
+		require.NoError(t, err)
+		assert.Equal(t, "p@ssw0rd", copied.Key("secret").String())
+		require.NoError(t, f2.RenameSection("backup", "replica"))
+
+		var out bytes.Buffer
+		_, err = f2.WriteTo(&out)
+		require.NoError(t, err)
+		f3, err := Load(out.Bytes())
+		require.NoError(t, err)
+		f3.SetKeyring(kr)
+		assert.Equal(t, "p@ssw0rd", f3.Section("database").Key("secret").String())
+		assert.Equal(t, "p@ssw0rd", f3.Section("replica").Key("secret").String())
+	})
+
+	t.Run("rename without keyring", func(t *testing.T) {
+		f2, err := Load(buf.Bytes())
+		require.NoError(t, err)
+
+		// Renamed values cannot be written until re-encrypted.
+		require.NoError(t, f2.Section("database").RenameKey("password", "secret"))
+		_, err = f2.WriteTo(ioutil.Discard)
+		require.Error(t, err)
+
+		f2.SetKeyring(kr)
+		require.NoError(t, f2.Reencrypt())
+		var out bytes.Buffer
+		_, err = f2.WriteTo(&out)
+		require.NoError(t, err)
+		f3, err := Load(out.Bytes())
+		require.NoError(t, err)
+		f3.SetKeyring(kr)
+		assert.Equal(t, "p@ssw0rd", f3.Section("database").Key("secret").String())
+	})
+
+	t.Run("interpolate references", func(t *testing.T) {
+		f2, err := LoadSources(LoadOptions{ExtendedInterpolation: true}, buf.Bytes())
+		require.NoError(t, err)
+		f2.SetKeyring(kr)
+
+		dsn := f2.Section("").Key("dsn")
+		dsn.SetValue("root:${database:password}@localhost:${database:port}")
+		assert.Equal(t, "root:p@ssw0rd@localhost:3306", dsn.String())
+		val, err := dsn.Resolve()
+		require.NoError(t, err)
+		assert.Equal(t, "root:p@ssw0rd@localhost:3306", val)
+	})
+
+	t.Run("invalid keys", func(t *testing.T) {
+		_, err := NewKeyring("k1", []byte("short"))
+		require.Error(t, err)
+		_, err = NewKeyring("k:1", bytes.Repeat([]byte{1}, 32))
+		require.Error(t, err)
+		require.Error(t, kr.SetPrimary("404"))
+		require.Error(t, key.SetSecret("p@ssw0rd", nil))
+	})
+}
+
+func TestFile_Reencrypt(t *testing.T) {
+	kr, err := NewKeyring("old", bytes.Repeat([]byte{1}, 32))
+	require.NoError(t, err)
+
+	f, err := LoadSources(LoadOptions{AllowShadows: true}, []byte(`
+[database]
+password = placeholder
+token = first
+token = second
+`))
+	require.NoError(t, err)
+	require.NotNil(t, f)
+	require.Error(t, f.Reencrypt())
+
+	f.SetKeyring(kr)
+	sec := f.Section("database")
+	require.NoError(t, sec.Key("password").SetSecret("p@ssw0rd", kr))
+	token := sec.Key("token")
+	require.NoError(t, token.SetSecret("first", kr))
+	require.NoError(t, token.shadows[0].SetSecret("second", kr))
+
+	require.NoError(t, kr.Add("new", bytes.Repeat([]byte{2}, 32)))
+	require.NoError(t, kr.SetPrimary("new"))
+	assert.Equal(t, "new", kr.Primary())
+	require.NoError(t, f.Reencrypt())
+
+	assert.True(t, strings.HasSuffix(sec.Key("password").Value(), ",kid:new]"))
+	assert.Equal(t, "p@ssw0rd", sec.Key("password").String())
+	for _, val := range token.ValueWithShadows() {
+		assert.True(t, strings.HasSuffix(val, ",kid:new]"))
+	}
+
+	newOnly, err := NewKeyring("new", bytes.Repeat([]byte{2}, 32))
+	require.NoError(t, err)
+	f.SetKeyring(newOnly)
+	assert.Equal(t, "p@ssw0rd", sec.Key("password").String())
+	assert.Equal(t, "first", token.String())
+	assert.Equal(t, "second", token.shadows[0].String())
+}
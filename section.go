@@ -22,15 +22,19 @@ import (
 
 // Section represents a config section.
 type Section struct {
-	f        *File
-	Comment  string
-	name     string
-	keys     map[string]*Key
-	keyList  []string
-	keysHash map[string]string
+	f             *File
+	Comment       string
+	InlineComment string
+	name          string
+	keys          map[string]*Key
+	keyList       []string
+	keysHash      map[string]string
 
 	isRawSection bool
 	rawBody      string
+
+	// The original spelling of name with PreserveCase.
+	spelling string
 }
 
 func newSection(f *File, name string) *Section {
@@ -45,17 +49,39 @@ func newSection(f *File, name string) *Section {
 
 // Name returns name of Section.
 func (s *Section) Name() string {
+	if s.f.BlockMode {
+		s.f.lock.RLock()
+		defer s.f.lock.RUnlock()
+	}
+	return s.displayName()
+}
+
+// displayName returns the original spelling of the name with PreserveCase, and
+// the name otherwise. The caller must hold the lock.
+func (s *Section) displayName() string {
+	if s.f.options.PreserveCase && len(s.spelling) > 0 {
+		return s.spelling
+	}
 	return s.name
 }
 
 // Body returns rawBody of Section if the section was marked as unparseable.
 // It still follows the other rules of the INI format surrounding leading/trailing whitespace.
 func (s *Section) Body() string {
+	if s.f.BlockMode {
+		s.f.lock.RLock()
+		defer s.f.lock.RUnlock()
+	}
 	return strings.TrimSpace(s.rawBody)
 }
 
 // SetBody updates body content only if section is raw.
 func (s *Section) SetBody(body string) {
+	if s.f.BlockMode {
+		s.f.lock.Lock()
+		defer s.f.lock.Unlock()
+	}
+
 	if !s.isRawSection {
 		return
 	}
@@ -64,18 +90,31 @@ func (s *Section) SetBody(body string) {
 
 // NewKey creates a new key to given section.
 func (s *Section) NewKey(name, val string) (*Key, error) {
-	if len(name) == 0 {
-		return nil, errors.New("error creating new key: empty key name")
-	} else if s.f.options.Insensitive || s.f.options.InsensitiveKeys {
-		name = strings.ToLower(name)
-	}
-
 	if s.f.BlockMode {
 		s.f.lock.Lock()
 		defer s.f.lock.Unlock()
 	}
+	return s.newKey(name, val)
+}
+
+// keyName returns the name of key stored in the section for given name.
+func (s *Section) keyName(name string) string {
+	if s.f.options.Insensitive || s.f.options.InsensitiveKeys {
+		return foldCase(name, s.f.options.UnicodeCaseFolding)
+	}
+	return name
+}
 
-	if inSlice(name, s.keyList) {
+// newKey creates a new key, or sets the value of the existing one. The caller must hold the lock.
+func (s *Section) newKey(name, val string) (*Key, error) {
+	if len(name) == 0 {
+		return nil, errors.New("error creating new key: empty key name")
+	}
+	spelling := name
+	name = s.keyName(name)
+
+	// The keys map indexes all names of the key list.
+	if _, ok := s.keys[name]; ok {
 		if s.f.options.AllowShadows {
 			if err := s.keys[name].addShadow(val); err != nil {
 				return nil, err
@@ -87,15 +126,88 @@ func (s *Section) NewKey(name, val string) (*Key, error) {
 		return s.keys[name], nil
 	}
 
+	key := newKey(s, name, val)
+	key.spelling = spelling
 	s.keyList = append(s.keyList, name)
-	s.keys[name] = newKey(s, name, val)
+	s.keys[name] = key
 	s.keysHash[name] = val
-	return s.keys[name], nil
+	return key, nil
+}
+
+// NewKeyAt creates a new key at given position of the key list, where 0 is the
+// first position and the number of keys is the last one. It returns an error if
+// the key already exists.
+func (s *Section) NewKeyAt(index int, name, val string) (*Key, error) {
+	if s.f.BlockMode {
+		s.f.lock.Lock()
+		defer s.f.lock.Unlock()
+	}
+
+	if index < 0 || index > len(s.keyList) {
+		return nil, fmt.Errorf("index %d out of range [0, %d]", index, len(s.keyList))
+	}
+	return s.insertKey(index, name, val)
+}
+
+// NewKeyBefore creates a new key right before the anchor key. It returns an error
+// if the key already exists.
+func (s *Section) NewKeyBefore(anchor, name, val string) (*Key, error) {
+	return s.newKeyNextTo(PlaceBefore, anchor, name, val)
+}
+
+// NewKeyAfter creates a new key right after the anchor key. It returns an error
+// if the key already exists.
+func (s *Section) NewKeyAfter(anchor, name, val string) (*Key, error) {
+	return s.newKeyNextTo(PlaceAfter, anchor, name, val)
+}
+
+func (s *Section) newKeyNextTo(place Placement, anchor, name, val string) (*Key, error) {
+	if s.f.BlockMode {
+		s.f.lock.Lock()
+		defer s.f.lock.Unlock()
+	}
+
+	index := indexOfKey(s.keyList, s.keyName(anchor))
+	if index == -1 {
+		return nil, fmt.Errorf("anchor key %q does not exist", s.keyName(anchor))
+	}
+	if place == PlaceAfter {
+		index++
+	}
+	return s.insertKey(index, name, val)
+}
+
+// insertKey creates a new key at given position of the key list. The caller must hold the lock.
+func (s *Section) insertKey(index int, name, val string) (*Key, error) {
+	if len(name) == 0 {
+		return nil, errors.New("error creating new key: empty key name")
+	}
+	name = s.keyName(name)
+	if _, ok := s.keys[name]; ok {
+		return nil, fmt.Errorf("key %q already exists", name)
+	}
+
+	key, err := s.newKey(name, val)
+	if err != nil {
+		return nil, err
+	}
+	// The new key has been appended to the end.
+	s.keyList = insertKeyName(s.keyList[:len(s.keyList)-1], index, name)
+	return key, nil
 }
 
 // NewBooleanKey creates a new boolean type key to given section.
 func (s *Section) NewBooleanKey(name string) (*Key, error) {
-	key, err := s.NewKey(name, "true")
+	if s.f.BlockMode {
+		s.f.lock.Lock()
+		defer s.f.lock.Unlock()
+	}
+	return s.newBooleanKey(name)
+}
+
+// newBooleanKey is like NewBooleanKey. The caller must hold the lock.
+func (s *Section) newBooleanKey(name string) (*Key, error) {
+	key, err := s.newKey(name, "true")
 	if err != nil {
 		return nil, err
 	}
@@ -108,32 +220,55 @@ func (s *Section) NewBooleanKey(name string) (*Key, error) {
 func (s *Section) GetKey(name string) (*Key, error) {
 	if s.f.BlockMode {
 		s.f.lock.RLock()
+		defer s.f.lock.RUnlock()
 	}
-	if s.f.options.Insensitive || s.f.options.InsensitiveKeys {
-		name = strings.ToLower(name)
+
+	key := s.getKey(name)
+	if key == nil {
+		return nil, fmt.Errorf("error when getting key of section %q: key %q not exists", s.name, s.keyName(name))
 	}
-	key := s.keys[name]
-	if s.f.BlockMode {
-		s.f.lock.RUnlock()
+	return key, nil
+}
+
+// getKey returns key in section by given name, including keys of parent sections
+// and the default section, or nil if not found. The caller must hold the lock.
+func (s *Section) getKey(name string) *Key {
+	name = s.keyName(name)
+	if key := s.keys[name]; key != nil {
+		return key
 	}
 
-	if key == nil {
-		// Check if it is a child-section.
-		sname := s.name
-		for {
-			if i := strings.LastIndex(sname, s.f.options.ChildSectionDelimiter); i > -1 {
-				sname = sname[:i]
-				sec, err := s.f.GetSection(sname)
-				if err != nil {
-					continue
-				}
-				return sec.GetKey(name)
+	// Check if it is inherited from sections listed in the directive key.
+	if s.f.options.SectionInheritance && name != s.keyName(s.f.options.SectionInheritanceKey) {
+		for _, base := range s.inheritedSections() {
+			if key := base.keys[name]; key != nil {
+				return key.inheritBy(s)
 			}
-			break
 		}
-		return nil, fmt.Errorf("error when getting key of section %q: key %q not exists", s.name, name)
 	}
-	return key, nil
+
+	// Check if it is a child-section.
+	sname := s.name
+	for !s.f.options.FlatSections {
+		if i := strings.LastIndex(sname, s.f.options.ChildSectionDelimiter); i > -1 {
+			sname = sname[:i]
+			secs := s.f.sectionsByName(sname)
+			if len(secs) == 0 {
+				continue
+			}
+			return secs[0].getKey(name)
+		}
+		break
+	}
+
+	// Check if it is inherited from the default section, in which case references
+	// in values are resolved in current section as Python's configparser does.
+	if def := s.defaultSection(); def != nil {
+		if key := def.getKey(name); key != nil {
+			return key.inheritBy(s)
+		}
+	}
+	return nil
 }
 
 // HasKey returns true if section contains a key with given name.
@@ -165,20 +300,116 @@ func (s *Section) HasValue(value string) bool {
 // Key assumes named Key exists in section and returns a zero-value when not.
 func (s *Section) Key(name string) *Key {
 	key, err := s.GetKey(name)
-	if err != nil {
-		// It's OK here because the only possible error is empty key name,
-		// but if it's empty, this piece of code won't be executed.
-		key, _ = s.NewKey(name, "")
+	if err == nil {
+		return key
+	}
+
+	if s.f.BlockMode {
+		s.f.lock.Lock()
+		defer s.f.lock.Unlock()
+	}
+	// Check again in case it was created by others meanwhile.
+	if key = s.getKey(name); key != nil {
 		return key
 	}
+	// It's OK here because the only possible error is empty key name,
+	// but if it's empty, this piece of code won't be executed.
+	key, _ = s.newKey(name, "")
 	return key
 }
 
+// defaultSection returns the default section when keys of it are inherited by
+// current section, or nil otherwise. The caller must hold the lock.
+func (s *Section) defaultSection() *Section {
+	if !s.f.options.InheritDefaultSection {
+		return nil
+	}
+	secs := s.f.sectionsByName("")
+	if len(secs) == 0 || secs[0] == s {
+		return nil
+	}
+	return secs[0]
+}
+
+// inheritedKeyStrings returns names of keys inherited from sections listed in
+// the directive key and the default section, and not overridden by current
+// section. The caller must hold the lock.
+func (s *Section) inheritedKeyStrings() []string {
+	var bases []*Section
+	if s.f.options.SectionInheritance {
+		bases = s.inheritedSections()
+	}
+	if def := s.defaultSection(); def != nil {
+		bases = append(bases, def)
+	}
+	if len(bases) == 0 {
+		return nil
+	}
+
+	directive := s.keyName(s.f.options.SectionInheritanceKey)
+	seen := make(map[string]bool)
+	var names []string
+	for _, base := range bases {
+		for _, name := range base.keyList {
+			if _, ok := s.keys[name]; ok || seen[name] || name == directive {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
 // Keys returns list of keys of section.
 func (s *Section) Keys() []*Key {
-	keys := make([]*Key, len(s.keyList))
-	for i := range s.keyList {
-		keys[i] = s.Key(s.keyList[i])
+	if s.f.BlockMode {
+		s.f.lock.RLock()
+		defer s.f.lock.RUnlock()
+	}
+
+	names := s.keyStrings()
+	keys := make([]*Key, len(names))
+	for i, name := range names {
+		keys[i] = s.getKey(name)
+	}
+	return keys
+}
+
+// OwnKeys returns list of keys defined in the section itself, excluding the
+// directive key of SectionInheritance.
+func (s *Section) OwnKeys() []*Key {
+	if s.f.BlockMode {
+		s.f.lock.RLock()
+		defer s.f.lock.RUnlock()
+	}
+
+	directive := s.keyName(s.f.options.SectionInheritanceKey)
+	keys := make([]*Key, 0, len(s.keyList))
+	for _, name := range s.keyList {
+		if !s.f.options.SectionInheritance || name != directive {
+			keys = append(keys, s.keys[name])
+		}
+	}
+	return keys
+}
+
+// EffectiveKeys returns list of keys visible in the section, i.e. own keys
+// followed by keys inherited through SectionInheritance and from the default
+// section with InheritDefaultSection. The directive key is excluded.
+func (s *Section) EffectiveKeys() []*Key {
+	if s.f.BlockMode {
+		s.f.lock.RLock()
+		defer s.f.lock.RUnlock()
+	}
+
+	directive := s.keyName(s.f.options.SectionInheritanceKey)
+	names := s.keyStrings()
+	keys := make([]*Key, 0, len(names))
+	for _, name := range names {
+		if !s.f.options.SectionInheritance || name != directive {
+			keys = append(keys, s.getKey(name))
+		}
 	}
 	return keys
 }
@@ -186,7 +417,7 @@ func (s *Section) Keys() []*Key {
 // ParentKeys returns list of keys of parent section.
 func (s *Section) ParentKeys() []*Key {
 	var parentKeys []*Key
-	sname := s.name
+	sname := s.Name()
 	for {
 		if i := strings.LastIndex(sname, s.f.options.ChildSectionDelimiter); i > -1 {
 			sname = sname[:i]
@@ -205,9 +436,26 @@ func (s *Section) ParentKeys() []*Key {
 
 // KeyStrings returns list of key names of section.
 func (s *Section) KeyStrings() []string {
-	list := make([]string, len(s.keyList))
+	if s.f.BlockMode {
+		s.f.lock.RLock()
+		defer s.f.lock.RUnlock()
+	}
+
+	names := s.keyStrings()
+	if s.f.options.PreserveCase {
+		for i, name := range names {
+			names[i] = s.getKey(name).displayName()
+		}
+	}
+	return names
+}
+
+// keyStrings is like KeyStrings. The caller must hold the lock.
+func (s *Section) keyStrings() []string {
+	inherited := s.inheritedKeyStrings()
+	list := make([]string, len(s.keyList), len(s.keyList)+len(inherited))
 	copy(list, s.keyList)
-	return list
+	return append(list, inherited...)
 }
 
 // KeysHash returns keys hash consisting of names and values.
@@ -217,20 +465,147 @@ func (s *Section) KeysHash() map[string]string {
 		defer s.f.lock.RUnlock()
 	}
 
+	def := s.defaultSection()
 	hash := make(map[string]string, len(s.keysHash))
+	if def != nil {
+		for key, value := range def.keysHash {
+			hash[key] = value
+		}
+	}
+	if s.f.options.SectionInheritance {
+		directive := s.keyName(s.f.options.SectionInheritanceKey)
+		bases := s.inheritedSections()
+		for i := len(bases) - 1; i >= 0; i-- {
+			for key, value := range bases[i].keysHash {
+				if key != directive {
+					hash[key] = value
+				}
+			}
+		}
+	}
 	for key, value := range s.keysHash {
 		hash[key] = value
 	}
+
+	if s.f.options.PreserveCase {
+		spelled := make(map[string]string, len(hash))
+		for key, value := range hash {
+			spelled[s.getKey(key).displayName()] = value
+		}
+		return spelled
+	}
 	return hash
 }
 
+// RenameKey renames the key, keeping its position, comments and shadows.
+func (s *Section) RenameKey(oldName, newName string) error {
+	if len(newName) == 0 {
+		return errors.New("empty key name")
+	}
+
+	if s.f.BlockMode {
+		s.f.lock.Lock()
+		defer s.f.lock.Unlock()
+	}
+
+	spelling := newName
+	oldName, newName = s.keyName(oldName), s.keyName(newName)
+	key := s.keys[oldName]
+	if key == nil {
+		return fmt.Errorf("key %q does not exist", oldName)
+	} else if _, ok := s.keys[newName]; ok && oldName != newName {
+		return fmt.Errorf("key %q already exists", newName)
+	}
+
+	key.spelling = spelling
+	for _, shadow := range key.shadows {
+		shadow.spelling = spelling
+	}
+	if oldName == newName {
+		// Only the spelling may change.
+		return nil
+	}
+
+	key.pinSecret()
+	key.name = newName
+	// A renamed key is written with its name rather than "-".
+	key.isAutoIncrement = false
+	for _, shadow := range key.shadows {
+		shadow.name = newName
+	}
+	s.keyList[indexOfKey(s.keyList, oldName)] = newName
+	delete(s.keys, oldName)
+	s.keys[newName] = key
+	s.keysHash[newName] = s.keysHash[oldName]
+	delete(s.keysHash, oldName)
+	for _, k := range append([]*Key{key}, key.shadows...) {
+		k.rebindSecret()
+	}
+	return nil
+}
+
+// MoveKey moves the key to be right before or after the anchor key.
+func (s *Section) MoveKey(name string, place Placement, anchor string) error {
+	if s.f.BlockMode {
+		s.f.lock.Lock()
+		defer s.f.lock.Unlock()
+	}
+
+	name, anchor = s.keyName(name), s.keyName(anchor)
+	i := indexOfKey(s.keyList, name)
+	if i == -1 {
+		return fmt.Errorf("key %q does not exist", name)
+	} else if name == anchor {
+		return fmt.Errorf("cannot move key %q relative to itself", name)
+	}
+	s.keyList = append(s.keyList[:i], s.keyList[i+1:]...)
+
+	j := indexOfKey(s.keyList, anchor)
+	if j == -1 {
+		// Put it back before reporting the error.
+		s.keyList = insertKeyName(s.keyList, i, name)
+		return fmt.Errorf("anchor key %q does not exist", anchor)
+	}
+	if place == PlaceAfter {
+		j++
+	}
+	s.keyList = insertKeyName(s.keyList, j, name)
+	return nil
+}
+
+// indexOfKey returns the index of the key name in the list, or -1 if not found.
+func indexOfKey(list []string, name string) int {
+	for i := range list {
+		if list[i] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertKeyName inserts the key name into the list at given index.
+func insertKeyName(list []string, index int, name string) []string {
+	list = append(list, "")
+	copy(list[index+1:], list[index:])
+	list[index] = name
+	return list
+}
+
 // DeleteKey deletes a key from section.
 func (s *Section) DeleteKey(name string) {
 	if s.f.BlockMode {
 		s.f.lock.Lock()
 		defer s.f.lock.Unlock()
 	}
+	s.deleteKey(name)
+}
 
+// deleteKey deletes a key by given name. The caller must hold the lock.
+func (s *Section) deleteKey(name string) {
+	name = s.keyName(name)
+	if _, ok := s.keys[name]; !ok {
+		return
+	}
 	for i, k := range s.keyList {
 		if k == name {
 			s.keyList = append(s.keyList[:i], s.keyList[i+1:]...)
@@ -245,6 +620,11 @@ func (s *Section) DeleteKey(name string) {
 // For example, "[parent.child1]" and "[parent.child12]" are child sections
 // of section "[parent]".
 func (s *Section) ChildSections() []*Section {
+	if s.f.BlockMode {
+		s.f.lock.RLock()
+		defer s.f.lock.RUnlock()
+	}
+
 	prefix := s.name + s.f.options.ChildSectionDelimiter
 	children := make([]*Section, 0, 3)
 	for _, name := range s.f.sectionList {
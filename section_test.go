@@ -15,6 +15,7 @@
 package ini
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -313,3 +314,118 @@ func TestSection_DeleteKey(t *testing.T) {
 		assert.False(t, f.Section("").HasKey("NAME"))
 	})
 }
+
+func TestSection_RenameKey(t *testing.T) {
+	t.Run("rename a key", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{AllowShadows: true}, []byte(`
+[server]
+host = localhost
+; The port to listen
+port = 80
+port = 8080
+user = root
+`))
+		require.NoError(t, err)
+
+		sec := f.Section("server")
+		require.NoError(t, sec.RenameKey("port", "listen"))
+		assert.Equal(t, []string{"host", "listen", "user"}, sec.KeyStrings())
+		assert.False(t, sec.HasKey("port"))
+		assert.Equal(t, "; The port to listen", sec.Key("listen").Comment)
+		assert.Equal(t, []string{"80", "8080"}, sec.Key("listen").ValueWithShadows())
+		assert.Equal(t, "80", sec.KeysHash()["listen"])
+
+		var buf bytes.Buffer
+		_, err = f.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "listen = 8080")
+	})
+
+	t.Run("rename with insensitive keys", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{InsensitiveKeys: true}, []byte("Name = ini"))
+		require.NoError(t, err)
+
+		sec := f.Section("")
+		require.NoError(t, sec.RenameKey("NAME", "Title"))
+		assert.Equal(t, []string{"title"}, sec.KeyStrings())
+		assert.Equal(t, "ini", sec.Key("TITLE").String())
+	})
+
+	t.Run("bad renames", func(t *testing.T) {
+		f, err := Load([]byte("a = 1\nb = 2"))
+		require.NoError(t, err)
+
+		sec := f.Section("")
+		assert.Error(t, sec.RenameKey("c", "d"))
+		assert.Error(t, sec.RenameKey("a", "b"))
+		assert.Error(t, sec.RenameKey("a", ""))
+		assert.NoError(t, sec.RenameKey("a", "a"))
+	})
+}
+
+func TestSection_MoveKey(t *testing.T) {
+	f, err := Load([]byte("a = 1\nb = 2\nc = 3"))
+	require.NoError(t, err)
+	sec := f.Section("")
+
+	require.NoError(t, sec.MoveKey("a", PlaceAfter, "c"))
+	assert.Equal(t, []string{"b", "c", "a"}, sec.KeyStrings())
+
+	require.NoError(t, sec.MoveKey("a", PlaceBefore, "b"))
+	assert.Equal(t, []string{"a", "b", "c"}, sec.KeyStrings())
+
+	require.NoError(t, sec.MoveKey("c", PlaceBefore, "b"))
+	assert.Equal(t, []string{"a", "c", "b"}, sec.KeyStrings())
+
+	assert.Error(t, sec.MoveKey("d", PlaceBefore, "a"))
+	assert.Error(t, sec.MoveKey("a", PlaceBefore, "a"))
+	assert.Error(t, sec.MoveKey("a", PlaceBefore, "d"))
+	assert.Equal(t, []string{"a", "c", "b"}, sec.KeyStrings())
+}
+
+func TestSection_NewKeyAt(t *testing.T) {
+	t.Run("insert keys at positions", func(t *testing.T) {
+		f, err := Load([]byte(`
+[server]
+host = localhost
+port = 80
+`))
+		require.NoError(t, err)
+		sec := f.Section("server")
+
+		k, err := sec.NewKeyAfter("port", "timeout", "30")
+		require.NoError(t, err)
+		k.Comment = "Timeout in seconds"
+
+		_, err = sec.NewKeyBefore("host", "scheme", "http")
+		require.NoError(t, err)
+		_, err = sec.NewKeyAt(2, "path", "/")
+		require.NoError(t, err)
+		_, err = sec.NewKeyAt(5, "user", "root")
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"scheme", "host", "path", "port", "timeout", "user"}, sec.KeyStrings())
+		assert.Equal(t, "30", sec.KeysHash()["timeout"])
+
+		var buf bytes.Buffer
+		_, err = f.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "port    = 80\n; Timeout in seconds\ntimeout = 30\n")
+	})
+
+	t.Run("bad insertions", func(t *testing.T) {
+		f, err := Load([]byte("a = 1"))
+		require.NoError(t, err)
+		sec := f.Section("")
+
+		_, err = sec.NewKeyAfter("b", "c", "3")
+		assert.Error(t, err)
+		_, err = sec.NewKeyAfter("a", "a", "3")
+		assert.Error(t, err)
+		_, err = sec.NewKeyAt(2, "c", "3")
+		assert.Error(t, err)
+		_, err = sec.NewKeyAt(0, "", "3")
+		assert.Error(t, err)
+		assert.Equal(t, []string{"a"}, sec.KeyStrings())
+	})
+}
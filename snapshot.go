@@ -0,0 +1,269 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Snapshot is an immutable view of sections and resolved values of a file at
+// a point in time. It is safe for concurrent use without any locking, and is
+// not affected by later changes of the file.
+type Snapshot struct {
+	// The order of creation among snapshots of the same file.
+	generation  uint64
+	options     LoadOptions
+	sectionList []*SnapshotSection
+	sections    map[string]*SnapshotSection
+}
+
+// SnapshotSection is an immutable view of a section in a snapshot.
+type SnapshotSection struct {
+	snap    *Snapshot
+	name    string
+	keyList []string
+	// Resolved values of keys including their shadows, keyed by key names.
+	values map[string][]string
+}
+
+// Snapshot returns the most recently published snapshot of the file, or
+// publishes one of the current content if there is none. Loading the snapshot
+// never blocks, even when the file is being modified or reloaded.
+//
+// Once a snapshot is published, Reload publishes a new one after parsing data
+// sources. Other changes are only visible in snapshots published afterwards
+// by PublishSnapshot.
+func (f *File) Snapshot() *Snapshot {
+	if snap, ok := f.snapshot.Load().(*Snapshot); ok {
+		return snap
+	}
+	return f.PublishSnapshot()
+}
+
+// PublishSnapshot creates a snapshot of the current content and publishes it
+// to be returned by subsequent calls of Snapshot. When snapshots are published
+// concurrently, the one of the latest content wins and is returned.
+func (f *File) PublishSnapshot() *Snapshot {
+	return f.publishSnapshot(f.newSnapshot())
+}
+
+// publishSnapshot publishes given snapshot unless a newer one is published, and
+// returns the published one.
+func (f *File) publishSnapshot(snap *Snapshot) *Snapshot {
+	f.snapshotLock.Lock()
+	defer f.snapshotLock.Unlock()
+	if cur, ok := f.snapshot.Load().(*Snapshot); ok && cur.generation > snap.generation {
+		return cur
+	}
+	f.snapshot.Store(snap)
+	return snap
+}
+
+// newSnapshot creates a snapshot of the current content. Values are resolved
+// the same way as Key.String, i.e. with interpolation and decryption applied.
+func (f *File) newSnapshot() *Snapshot {
+	// Copy the content at once, then resolve values without holding the lock.
+	if f.BlockMode {
+		f.lock.RLock()
+	}
+	c := f.clone()
+	f.snapshotLock.Lock()
+	f.snapshotGeneration++
+	generation := f.snapshotGeneration
+	f.snapshotLock.Unlock()
+	if f.BlockMode {
+		f.lock.RUnlock()
+	}
+	// Nothing else has access to the copy.
+	c.BlockMode = false
+	f.resolverCacheLock.RLock()
+	for k, v := range f.resolverCache {
+		if c.resolverCache == nil {
+			c.resolverCache = make(map[string]string, len(f.resolverCache))
+		}
+		c.resolverCache[k] = v
+	}
+	f.resolverCacheLock.RUnlock()
+
+	snap := &Snapshot{
+		generation: generation,
+		options:    f.options,
+		sections:   make(map[string]*SnapshotSection),
+	}
+	for _, sec := range c.Sections() {
+		ss := &SnapshotSection{
+			snap:   snap,
+			name:   sec.Name(),
+			values: make(map[string][]string),
+		}
+		// Include keys inherited from the default section.
+		for _, key := range sec.Keys() {
+			ss.keyList = append(ss.keyList, key.Name())
+			ss.values[ss.keyName(key.Name())] = key.resolvedValues()
+		}
+
+		snap.sectionList = append(snap.sectionList, ss)
+		// The first one wins for non-unique sections, as File.GetSection does.
+		if name := snap.sectionName(ss.name); snap.sections[name] == nil {
+			snap.sections[name] = ss
+		}
+	}
+	return snap
+}
+
+// resolvedValues returns resolved values of the key and its non-empty shadows.
+func (k *Key) resolvedValues() []string {
+	keys := k.withShadows()
+	vals := []string{k.String()}
+	for _, s := range keys[1:] {
+		if s.Value() != "" {
+			vals = append(vals, s.String())
+		}
+	}
+	return vals
+}
+
+// sectionName returns the normalized section name for lookups.
+func (s *Snapshot) sectionName(name string) string {
+	if len(name) == 0 {
+		return DefaultSection
+	}
+	if s.options.Insensitive || s.options.InsensitiveSections {
+		return foldCase(name, s.options.UnicodeCaseFolding)
+	}
+	return name
+}
+
+// GetSection returns the section by given name.
+func (s *Snapshot) GetSection(name string) (*SnapshotSection, error) {
+	sec := s.sections[s.sectionName(name)]
+	if sec == nil {
+		return nil, fmt.Errorf("section %q does not exist", name)
+	}
+	return sec, nil
+}
+
+// Section returns the section by given name, or an empty section if it does not exist.
+func (s *Snapshot) Section(name string) *SnapshotSection {
+	sec, err := s.GetSection(name)
+	if err != nil {
+		return &SnapshotSection{snap: s, name: s.sectionName(name)}
+	}
+	return sec
+}
+
+// HasSection returns true if the section with given name exists.
+func (s *Snapshot) HasSection(name string) bool {
+	_, ok := s.sections[s.sectionName(name)]
+	return ok
+}
+
+// Sections returns the list of sections.
+func (s *Snapshot) Sections() []*SnapshotSection {
+	list := make([]*SnapshotSection, len(s.sectionList))
+	copy(list, s.sectionList)
+	return list
+}
+
+// SectionStrings returns the list of section names.
+func (s *Snapshot) SectionStrings() []string {
+	list := make([]string, len(s.sectionList))
+	for i, sec := range s.sectionList {
+		list[i] = sec.name
+	}
+	return list
+}
+
+// Name returns the name of the section.
+func (s *SnapshotSection) Name() string {
+	return s.name
+}
+
+// keyName returns the normalized key name for lookups.
+func (s *SnapshotSection) keyName(name string) string {
+	if s.snap.options.Insensitive || s.snap.options.InsensitiveKeys {
+		return foldCase(name, s.snap.options.UnicodeCaseFolding)
+	}
+	return name
+}
+
+// lookup returns values of the key by given name, falling back to parent
+// sections as Section.GetKey does.
+func (s *SnapshotSection) lookup(name string) ([]string, bool) {
+	if vals, ok := s.values[s.keyName(name)]; ok {
+		return vals, true
+	}
+
+	sname := s.name
+	for {
+		i := strings.LastIndex(sname, s.snap.options.ChildSectionDelimiter)
+		if i == -1 {
+			return nil, false
+		}
+		sname = sname[:i]
+		if parent, ok := s.snap.sections[s.snap.sectionName(sname)]; ok {
+			return parent.lookup(name)
+		}
+	}
+}
+
+// HasKey returns true if the section contains the key with given name.
+func (s *SnapshotSection) HasKey(name string) bool {
+	_, ok := s.lookup(name)
+	return ok
+}
+
+// GetValue returns the resolved value of the key by given name, and false if
+// the key does not exist.
+func (s *SnapshotSection) GetValue(name string) (string, bool) {
+	vals, ok := s.lookup(name)
+	if !ok {
+		return "", false
+	}
+	return vals[0], true
+}
+
+// Value returns the resolved value of the key by given name, or an empty
+// string if the key does not exist.
+func (s *SnapshotSection) Value(name string) string {
+	val, _ := s.GetValue(name)
+	return val
+}
+
+// ValueWithShadows returns the resolved values of the key by given name
+// including its shadows.
+func (s *SnapshotSection) ValueWithShadows(name string) []string {
+	vals, _ := s.lookup(name)
+	list := make([]string, len(vals))
+	copy(list, vals)
+	return list
+}
+
+// KeyStrings returns the list of key names of the section.
+func (s *SnapshotSection) KeyStrings() []string {
+	list := make([]string, len(s.keyList))
+	copy(list, s.keyList)
+	return list
+}
+
+// KeysHash returns the resolved values of keys of the section.
+func (s *SnapshotSection) KeysHash() map[string]string {
+	hash := make(map[string]string, len(s.keyList))
+	for _, name := range s.keyList {
+		hash[name] = s.values[s.keyName(name)][0]
+	}
+	return hash
+}
@@ -0,0 +1,171 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Snapshot(t *testing.T) {
+	t.Run("read resolved values", func(t *testing.T) {
+		f, err := LoadSources(LoadOptions{
+			AllowShadows:          true,
+			InheritDefaultSection: true,
+			Insensitive:           true,
+		}, []byte(`
+NAME = app
+[server]
+HOST = localhost
+URL = http://%(HOST)s/%(NAME)s
+PORT = 80
+PORT = 8080
+[server.tls]
+CERT = cert.pem
+`))
+		require.NoError(t, err)
+
+		snap := f.Snapshot()
+		assert.Equal(t, []string{"default", "server", "server.tls"}, snap.SectionStrings())
+		assert.True(t, snap.HasSection("SERVER"))
+		assert.False(t, snap.HasSection("client"))
+
+		sec := snap.Section("server")
+		assert.Equal(t, "server", sec.Name())
+		assert.Equal(t, "http://localhost/app", sec.Value("url"))
+		assert.Equal(t, []string{"80", "8080"}, sec.ValueWithShadows("port"))
+		assert.Equal(t, []string{"host", "url", "port", "name"}, sec.KeyStrings())
+		assert.Equal(t, "app", sec.KeysHash()["name"])
+
+		// Keys of parent sections
+		val, ok := snap.Section("server.tls").GetValue("host")
+		assert.True(t, ok)
+		assert.Equal(t, "localhost", val)
+
+		// Nonexistent keys and sections
+		_, ok = sec.GetValue("missing")
+		assert.False(t, ok)
+		assert.False(t, snap.Section("client").HasKey("host"))
+		_, err = snap.GetSection("client")
+		assert.Error(t, err)
+	})
+
+	t.Run("publish changes", func(t *testing.T) {
+		f, err := Load([]byte("key = old"))
+		require.NoError(t, err)
+
+		snap := f.Snapshot()
+		assert.Equal(t, snap, f.Snapshot())
+
+		f.Section("").Key("key").SetValue("new")
+		assert.Equal(t, "old", f.Snapshot().Section("").Value("key"))
+
+		assert.Equal(t, "new", f.PublishSnapshot().Section("").Value("key"))
+		assert.Equal(t, "new", f.Snapshot().Section("").Value("key"))
+		// Old snapshots are not affected
+		assert.Equal(t, "old", snap.Section("").Value("key"))
+	})
+
+	t.Run("stale snapshots are not published", func(t *testing.T) {
+		f, err := Load([]byte("key = old"))
+		require.NoError(t, err)
+
+		stale := f.newSnapshot()
+		f.Section("").Key("key").SetValue("new")
+		latest := f.PublishSnapshot()
+
+		assert.Equal(t, latest, f.publishSnapshot(stale))
+		assert.Equal(t, "new", f.Snapshot().Section("").Value("key"))
+	})
+
+	t.Run("point in time", func(t *testing.T) {
+		f, err := Load([]byte("a = 0\nb = 0"))
+		require.NoError(t, err)
+		f.BlockMode = true
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 1; i <= 200; i++ {
+				v := strconv.Itoa(i)
+				_ = f.Update(func(tx *Tx) error {
+					if err := tx.SetValue("", "a", v); err != nil {
+						return err
+					}
+					return tx.SetValue("", "b", v)
+				})
+			}
+		}()
+		for i := 0; i < 200; i++ {
+			sec := f.PublishSnapshot().Section("")
+			assert.Equal(t, sec.Value("a"), sec.Value("b"))
+		}
+		<-done
+	})
+
+	t.Run("publish on reload", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "ini")
+		require.NoError(t, err)
+		defer func() { _ = os.RemoveAll(dir) }()
+
+		path := filepath.Join(dir, "app.ini")
+		require.NoError(t, ioutil.WriteFile(path, []byte("key = 1"), 0644))
+		f, err := Load(path)
+		require.NoError(t, err)
+		assert.Equal(t, "1", f.Snapshot().Section("").Value("key"))
+
+		require.NoError(t, ioutil.WriteFile(path, []byte("key = 2"), 0644))
+		require.NoError(t, f.Reload())
+		assert.Equal(t, "2", f.Snapshot().Section("").Value("key"))
+	})
+
+	t.Run("concurrent reads and reloads", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "ini")
+		require.NoError(t, err)
+		defer func() { _ = os.RemoveAll(dir) }()
+
+		path := filepath.Join(dir, "app.ini")
+		require.NoError(t, ioutil.WriteFile(path, []byte("key = 0"), 0644))
+		f, err := Load(path)
+		require.NoError(t, err)
+		f.BlockMode = true
+		f.Snapshot()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 1000; j++ {
+					_, err := strconv.Atoi(f.Snapshot().Section("").Value("key"))
+					assert.NoError(t, err)
+				}
+			}()
+		}
+		for i := 1; i <= 20; i++ {
+			require.NoError(t, ioutil.WriteFile(path, []byte("key = "+strconv.Itoa(i)), 0644))
+			require.NoError(t, f.Reload())
+		}
+		wg.Wait()
+		assert.Equal(t, "20", f.Snapshot().Section("").Value("key"))
+	})
+}
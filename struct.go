@@ -18,6 +18,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"reflect"
 	"strings"
 	"time"
@@ -77,6 +79,56 @@ func parseDelim(actual string) string {
 
 var reflectTime = reflect.TypeOf(time.Now()).Kind()
 
+// typeParsers parses values of types that are mapped to and from struct fields
+// by their types rather than their kinds.
+var typeParsers = map[reflect.Type]Parser{
+	reflect.TypeOf(ByteSize(0)):           func(str string) (interface{}, error) { return ParseByteSize(str) },
+	reflect.TypeOf(Percent(0)):            func(str string) (interface{}, error) { return ParsePercent(str) },
+	reflect.TypeOf(Rate{}):                func(str string) (interface{}, error) { return ParseRate(str) },
+	reflect.TypeOf(HostPort{}):            func(str string) (interface{}, error) { return ParseHostPort(str) },
+	reflect.TypeOf(net.IP(nil)):           func(str string) (interface{}, error) { return parseIP(str) },
+	reflect.TypeOf(net.HardwareAddr(nil)): func(str string) (interface{}, error) { return parseHardwareAddr(str) },
+	reflect.TypeOf(net.IPNet{}): func(str string) (interface{}, error) {
+		ipNet, err := parseIPNet(str)
+		if err != nil {
+			return net.IPNet{}, err
+		}
+		return *ipNet, nil
+	},
+	reflect.TypeOf(url.URL{}): func(str string) (interface{}, error) {
+		u, err := parseURL(str)
+		if err != nil {
+			return url.URL{}, err
+		}
+		return *u, nil
+	},
+}
+
+// isTypedValue returns true if given type, or the type it points to, is parsed
+// by typeParsers.
+func isTypedValue(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	_, ok := typeParsers[t]
+	return ok
+}
+
+// formatTypedValue returns the string representation of a value of a type in
+// typeParsers, which is empty for empty slices and zero structs.
+func formatTypedValue(v reflect.Value) string {
+	if (v.Kind() == reflect.Slice && v.Len() == 0) || (v.Kind() == reflect.Struct && v.IsZero()) {
+		return ""
+	}
+	// Some types only implement fmt.Stringer with pointer receivers, e.g. url.URL.
+	if v.CanAddr() {
+		if s, ok := v.Addr().Interface().(fmt.Stringer); ok {
+			return s.String()
+		}
+	}
+	return fmt.Sprint(v.Interface())
+}
+
 // setSliceWithProperType sets proper values to slice based on its type.
 func setSliceWithProperType(key *Key, field reflect.Value, delim string, allowShadow, isStrict bool) error {
 	var strs []string
@@ -91,6 +143,19 @@ func setSliceWithProperType(key *Key, field reflect.Value, delim string, allowSh
 		return nil
 	}
 
+	if parse, ok := typeParsers[field.Type().Elem()]; ok {
+		vals, err := key.doParse(strs, true, false, parse)
+		if err != nil && isStrict {
+			return err
+		}
+		slice := reflect.MakeSlice(field.Type(), numVals, numVals)
+		for i := 0; i < numVals; i++ {
+			slice.Index(i).Set(reflect.ValueOf(vals[i]))
+		}
+		field.Set(slice)
+		return nil
+	}
+
 	var vals interface{}
 	var err error
 
@@ -160,6 +225,26 @@ func setWithProperType(t reflect.Type, key *Key, field reflect.Value, delim stri
 	if isPtr {
 		vt = t.Elem()
 	}
+
+	if parse, ok := typeParsers[vt]; ok {
+		str, err := key.Resolve()
+		if err != nil {
+			return wrapStrictError(err, isStrict)
+		}
+		val, err := parse(str)
+		if err != nil {
+			return wrapStrictError(err, isStrict)
+		}
+		if isPtr {
+			pv := reflect.New(vt)
+			pv.Elem().Set(reflect.ValueOf(val))
+			field.Set(pv)
+		} else {
+			field.Set(reflect.ValueOf(val))
+		}
+		return nil
+	}
+
 	switch vt.Kind() {
 	case reflect.String:
 		stringVal := key.String()
@@ -300,6 +385,9 @@ func (s *Section) mapToField(val reflect.Value, isStrict bool, sectionIndex int,
 
 		isStruct := tpField.Type.Kind() == reflect.Struct
 		isStructPtr := tpField.Type.Kind() == reflect.Ptr && tpField.Type.Elem().Kind() == reflect.Struct
+		if isTypedValue(tpField.Type) {
+			isStruct, isStructPtr = false, false
+		}
 		isAnonymousPtr := tpField.Type.Kind() == reflect.Ptr && tpField.Anonymous
 		if isAnonymousPtr {
 			field.Set(reflect.New(tpField.Type.Elem()))
@@ -463,6 +551,23 @@ func reflectSliceWithProperType(key *Key, field reflect.Value, delim string, all
 	}
 	sliceOf := field.Type().Elem().Kind()
 
+	if _, ok := typeParsers[field.Type().Elem()]; ok {
+		vals := make([]string, field.Len())
+		for i := range vals {
+			vals[i] = formatTypedValue(slice.Index(i))
+		}
+		if allowShadow {
+			keyWithShadows := newKey(key.s, key.name, vals[0])
+			for _, val := range vals[1:] {
+				_ = keyWithShadows.AddShadow(val)
+			}
+			*key = *keyWithShadows
+			return nil
+		}
+		key.SetValue(strings.Join(vals, delim))
+		return nil
+	}
+
 	if allowShadow {
 		var keyWithShadows *Key
 		for i := 0; i < field.Len(); i++ {
@@ -477,7 +582,7 @@ func reflectSliceWithProperType(key *Key, field reflect.Value, delim string, all
 			case reflect.Float64:
 				val = fmt.Sprint(slice.Index(i).Float())
 			case reflect.Bool:
-				val = fmt.Sprint(slice.Index(i).Bool())
+				val = key.s.f.formatBool(slice.Index(i).Bool())
 			case reflectTime:
 				val = slice.Index(i).Interface().(time.Time).Format(time.RFC3339)
 			default:
@@ -506,7 +611,7 @@ func reflectSliceWithProperType(key *Key, field reflect.Value, delim string, all
 		case reflect.Float64:
 			buf.WriteString(fmt.Sprint(slice.Index(i).Float()))
 		case reflect.Bool:
-			buf.WriteString(fmt.Sprint(slice.Index(i).Bool()))
+			buf.WriteString(key.s.f.formatBool(slice.Index(i).Bool()))
 		case reflectTime:
 			buf.WriteString(slice.Index(i).Interface().(time.Time).Format(time.RFC3339))
 		default:
@@ -520,11 +625,16 @@ func reflectSliceWithProperType(key *Key, field reflect.Value, delim string, all
 
 // reflectWithProperType does the opposite thing as setWithProperType.
 func reflectWithProperType(t reflect.Type, key *Key, field reflect.Value, delim string, allowShadow bool) error {
+	if _, ok := typeParsers[t]; ok {
+		key.SetValue(formatTypedValue(field))
+		return nil
+	}
+
 	switch t.Kind() {
 	case reflect.String:
 		key.SetValue(field.String())
 	case reflect.Bool:
-		key.SetValue(fmt.Sprint(field.Bool()))
+		key.SetValue(key.s.f.formatBool(field.Bool()))
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		key.SetValue(fmt.Sprint(field.Int()))
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
@@ -562,6 +672,9 @@ func isEmptyValue(v reflect.Value) bool {
 	case reflect.Interface, reflect.Ptr:
 		return v.IsNil()
 	case reflectTime:
+		if _, ok := typeParsers[v.Type()]; ok {
+			return v.IsZero()
+		}
 		t, ok := v.Interface().(time.Time)
 		return ok && t.IsZero()
 	}
@@ -613,8 +726,9 @@ func (s *Section) reflectFrom(val reflect.Value) error {
 			continue
 		}
 
-		if (tpField.Type.Kind() == reflect.Ptr && tpField.Type.Elem().Kind() == reflect.Struct) ||
-			(tpField.Type.Kind() == reflect.Struct && tpField.Type.Name() != "Time") {
+		if !isTypedValue(tpField.Type) &&
+			((tpField.Type.Kind() == reflect.Ptr && tpField.Type.Elem().Kind() == reflect.Struct) ||
+				(tpField.Type.Kind() == reflect.Struct && tpField.Type.Name() != "Time")) {
 			// Note: The only error here is section doesn't exist.
 			sec, err := s.f.GetSection(fieldName)
 			if err != nil {
@@ -664,7 +778,7 @@ func (s *Section) reflectFrom(val reflect.Value) error {
 
 		// Note: Same reason as section.
 		key, err := s.GetKey(fieldName)
-		if err != nil {
+		if err != nil || key.inheritedBy != nil {
 			key, _ = s.NewKey(fieldName, "")
 		}
 
@@ -693,7 +807,7 @@ func (s *Section) ReflectFrom(v interface{}) error {
 		s.f.DeleteSection(s.name)
 
 		if typ.Kind() == reflect.Ptr {
-			sec, err := s.f.NewSection(s.name)
+			sec, err := s.f.NewSection(s.Name())
 			if err != nil {
 				return err
 			}
@@ -707,7 +821,7 @@ func (s *Section) ReflectFrom(v interface{}) error {
 		}
 
 		for i := 0; i < slice.Len(); i++ {
-			sec, err := s.f.NewSection(s.name)
+			sec, err := s.f.NewSection(s.Name())
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,36 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package ini
+
+import (
+	"net/netip"
+	"reflect"
+	"strings"
+)
+
+func init() {
+	typeParsers[reflect.TypeOf(netip.Addr{})] = func(str string) (interface{}, error) {
+		return netip.ParseAddr(strings.TrimSpace(str))
+	}
+	typeParsers[reflect.TypeOf(netip.Prefix{})] = func(str string) (interface{}, error) {
+		return netip.ParsePrefix(strings.TrimSpace(str))
+	}
+	typeParsers[reflect.TypeOf(netip.AddrPort{})] = func(str string) (interface{}, error) {
+		return netip.ParseAddrPort(strings.TrimSpace(str))
+	}
+}
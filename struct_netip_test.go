@@ -0,0 +1,70 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+//go:build go1.18
+// +build go1.18
+
+package ini
+
+import (
+	"bytes"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapToAndReflectFromNetip(t *testing.T) {
+	type server struct {
+		Addr   netip.Addr       `ini:"addr"`
+		Prefix *netip.Prefix    `ini:"prefix"`
+		Listen netip.AddrPort   `ini:"listen"`
+		Peers  []netip.AddrPort `ini:"peers"`
+		Unset  netip.Addr       `ini:"unset,omitempty"`
+	}
+
+	f, err := Load([]byte(`
+[server]
+addr = 10.0.0.1
+prefix = 10.0.0.0/8
+listen = [::1]:8080
+peers = 10.0.0.2:1, 10.0.0.3:2
+`))
+	require.NoError(t, err)
+
+	s := new(server)
+	require.NoError(t, f.Section("server").StrictMapTo(s))
+	assert.Equal(t, netip.MustParseAddr("10.0.0.1"), s.Addr)
+	require.NotNil(t, s.Prefix)
+	assert.Equal(t, netip.MustParsePrefix("10.0.0.0/8"), *s.Prefix)
+	assert.Equal(t, netip.MustParseAddrPort("[::1]:8080"), s.Listen)
+	assert.Equal(t, []netip.AddrPort{netip.MustParseAddrPort("10.0.0.2:1"), netip.MustParseAddrPort("10.0.0.3:2")}, s.Peers)
+
+	bad, err := Load([]byte("[server]\naddr = 10.0.0"))
+	require.NoError(t, err)
+	assert.Error(t, bad.Section("server").StrictMapTo(s))
+
+	cfg := Empty()
+	require.NoError(t, cfg.Section("server").ReflectFrom(s))
+	var buf bytes.Buffer
+	_, err = cfg.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, `[server]
+addr   = 10.0.0.1
+prefix = 10.0.0.0/8
+listen = [::1]:8080
+peers  = 10.0.0.2:1,10.0.0.3:2
+`, buf.String())
+}
@@ -0,0 +1,206 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Tx is a transaction of changes to a file, see File.Update. It must not be
+// used after the function passed to File.Update returns.
+type Tx struct {
+	f *File
+	// Functions to revert changes made so far, in the order of the changes.
+	undo []func()
+}
+
+// Update calls fn with a transaction to read and change the file. When BlockMode
+// is on, the file is locked for the duration of fn so that other goroutines
+// observe either none or all of the changes. Changes are reverted if fn returns
+// an error or panics.
+//
+// The file must not be accessed other than through the transaction within fn,
+// which would deadlock when BlockMode is on.
+func (f *File) Update(fn func(tx *Tx) error) error {
+	if err := f.update(fn); err != nil {
+		return err
+	}
+
+	// Replace the snapshot for readers once it is in use.
+	if f.snapshot.Load() != nil {
+		f.PublishSnapshot()
+	}
+	return nil
+}
+
+func (f *File) update(fn func(tx *Tx) error) (err error) {
+	if f.BlockMode {
+		f.lock.Lock()
+		defer f.lock.Unlock()
+	}
+
+	tx := &Tx{f: f}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.rollback()
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+// rollback reverts all changes made by the transaction.
+func (tx *Tx) rollback() {
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		tx.undo[i]()
+	}
+	tx.undo = nil
+}
+
+// section returns the first section with given name, and creates one if not exists.
+func (tx *Tx) section(name string) *Section {
+	if secs := tx.f.sectionsByName(name); len(secs) > 0 {
+		return secs[0]
+	}
+
+	if len(name) == 0 {
+		name = DefaultSection
+	}
+	sec := tx.f.newSection(name)
+	tx.undo = append(tx.undo, func() {
+		secs := tx.f.sectionsByName(sec.name)
+		for i := range secs {
+			if secs[i] == sec {
+				tx.f.deleteSectionWithIndex(sec.name, i)
+				return
+			}
+		}
+	})
+	return sec
+}
+
+// HasSection returns true if the file contains a section with given name.
+func (tx *Tx) HasSection(name string) bool {
+	return len(tx.f.sectionsByName(name)) > 0
+}
+
+// SectionStrings returns list of section names.
+func (tx *Tx) SectionStrings() []string {
+	list := make([]string, len(tx.f.sectionList))
+	for i, sec := range tx.f.sectionsInOrder() {
+		list[i] = sec.displayName()
+	}
+	return list
+}
+
+// KeyStrings returns list of key names of the section with given name.
+func (tx *Tx) KeyStrings(section string) []string {
+	secs := tx.f.sectionsByName(section)
+	if len(secs) == 0 {
+		return nil
+	}
+	names := secs[0].keyStrings()
+	for i, name := range names {
+		names[i] = secs[0].getKey(name).displayName()
+	}
+	return names
+}
+
+// Value returns the raw value of the key in the section with given names, and
+// false if the key does not exist. Keys of parent sections and the default
+// section are looked up as Section.GetKey does.
+func (tx *Tx) Value(section, key string) (string, bool) {
+	secs := tx.f.sectionsByName(section)
+	if len(secs) == 0 {
+		return "", false
+	}
+	k := secs[0].getKey(key)
+	if k == nil {
+		return "", false
+	}
+	return k.value, true
+}
+
+// SetValue sets the value of the key in the section with given names. The
+// section and the key are created if they do not exist.
+func (tx *Tx) SetValue(section, key, value string) error {
+	if len(key) == 0 {
+		return errors.New("empty key name")
+	}
+
+	sec := tx.section(section)
+	if k := sec.keys[sec.keyName(key)]; k != nil {
+		old := k.value
+		k.setValue(value)
+		tx.undo = append(tx.undo, func() { k.setValue(old) })
+		return nil
+	}
+
+	k, err := sec.newKey(key, value)
+	if err != nil {
+		return fmt.Errorf("new key: %v", err)
+	}
+	tx.undo = append(tx.undo, func() { sec.deleteKey(k.name) })
+	return nil
+}
+
+// DeleteKey deletes the key from the section with given names.
+func (tx *Tx) DeleteKey(section, key string) {
+	secs := tx.f.sectionsByName(section)
+	if len(secs) == 0 {
+		return
+	}
+	sec := secs[0]
+	name := sec.keyName(key)
+	k := sec.keys[name]
+	if k == nil {
+		return
+	}
+
+	idx := indexOfKey(sec.keyList, name)
+	hash := sec.keysHash[name]
+	sec.deleteKey(name)
+	tx.undo = append(tx.undo, func() {
+		sec.keyList = insertKeyName(sec.keyList, idx, name)
+		sec.keys[name] = k
+		sec.keysHash[name] = hash
+	})
+}
+
+// DeleteSection deletes all sections with given name.
+func (tx *Tx) DeleteSection(name string) {
+	f := tx.f
+	name = f.sectionName(name)
+	secs := f.sections[name]
+	if len(secs) == 0 {
+		return
+	}
+
+	sectionList := append([]string(nil), f.sectionList...)
+	sectionIndexes := append([]int(nil), f.sectionIndexes...)
+	secs = append([]*Section(nil), secs...)
+	f.deleteSection(name)
+	tx.undo = append(tx.undo, func() {
+		f.sectionList = sectionList
+		f.sectionIndexes = sectionIndexes
+		f.sections[name] = secs
+	})
+}
@@ -0,0 +1,201 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFile_Update(t *testing.T) {
+	t.Run("commit changes", func(t *testing.T) {
+		f, err := Load([]byte(`
+[db]
+host = localhost
+port = 3306
+[cache]
+size = 10
+`))
+		require.NoError(t, err)
+
+		err = f.Update(func(tx *Tx) error {
+			val, ok := tx.Value("db", "host")
+			assert.True(t, ok)
+			assert.Equal(t, "localhost", val)
+
+			require.NoError(t, tx.SetValue("db", "host", "db.local"))
+			require.NoError(t, tx.SetValue("db", "user", "root"))
+			require.NoError(t, tx.SetValue("log", "level", "debug"))
+			tx.DeleteKey("db", "port")
+			tx.DeleteSection("cache")
+
+			assert.True(t, tx.HasSection("log"))
+			assert.Equal(t, []string{"host", "user"}, tx.KeyStrings("db"))
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{DefaultSection, "db", "log"}, f.SectionStrings())
+		assert.Equal(t, []string{"host", "user"}, f.Section("db").KeyStrings())
+		assert.Equal(t, "db.local", f.Section("db").Key("host").String())
+		assert.Equal(t, "debug", f.Section("log").Key("level").String())
+	})
+
+	t.Run("rollback on error", func(t *testing.T) {
+		data := `[db]
+host = localhost
+port = 3306
+
+[cache]
+size = 10
+`
+		f, err := Load([]byte(data))
+		require.NoError(t, err)
+
+		errAbort := errors.New("abort")
+		err = f.Update(func(tx *Tx) error {
+			require.NoError(t, tx.SetValue("db", "host", "db.local"))
+			require.NoError(t, tx.SetValue("db", "user", "root"))
+			require.NoError(t, tx.SetValue("log", "level", "debug"))
+			tx.DeleteKey("db", "host")
+			tx.DeleteSection("cache")
+			require.NoError(t, tx.SetValue("cache", "size", "20"))
+			return errAbort
+		})
+		assert.Equal(t, errAbort, err)
+
+		var buf bytes.Buffer
+		_, err = f.WriteTo(&buf)
+		require.NoError(t, err)
+		assert.Equal(t, data, buf.String())
+		assert.Equal(t, map[string]string{"host": "localhost", "port": "3306"}, f.Section("db").KeysHash())
+	})
+
+	t.Run("rollback on panic", func(t *testing.T) {
+		f, err := Load([]byte("key = old"))
+		require.NoError(t, err)
+
+		func() {
+			defer func() { _ = recover() }()
+			_ = f.Update(func(tx *Tx) error {
+				_ = tx.SetValue("", "key", "new")
+				panic("oops")
+			})
+		}()
+		assert.Equal(t, "old", f.Section("").Key("key").String())
+
+		// The lock is released
+		f.Section("").Key("key").SetValue("new")
+		assert.Equal(t, "new", f.Section("").Key("key").String())
+	})
+
+	t.Run("publish snapshot", func(t *testing.T) {
+		f, err := Load([]byte("key = old"))
+		require.NoError(t, err)
+		assert.Equal(t, "old", f.Snapshot().Section("").Value("key"))
+
+		require.NoError(t, f.Update(func(tx *Tx) error {
+			return tx.SetValue("", "key", "new")
+		}))
+		assert.Equal(t, "new", f.Snapshot().Section("").Value("key"))
+	})
+}
+
+// TestFile_Concurrency exercises public APIs from multiple goroutines, which is
+// meant to be run with the race detector.
+func TestFile_Concurrency(t *testing.T) {
+	f, err := LoadSources(LoadOptions{AllowShadows: true, AllowNestedValues: true}, []byte(`
+[a]
+x = 0
+y = 0
+[a.b]
+z = 0
+[raw]
+`))
+	require.NoError(t, err)
+
+	const workers, iterations = 4, 200
+	var wg sync.WaitGroup
+	run := func(fn func(i int)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				fn(i)
+			}
+		}()
+	}
+
+	for w := 0; w < workers; w++ {
+		w := w
+		// Transactions keep x and y equal.
+		run(func(i int) {
+			assert.NoError(t, f.Update(func(tx *Tx) error {
+				val := strconv.Itoa(w*iterations + i)
+				if err := tx.SetValue("a", "x", val); err != nil {
+					return err
+				}
+				return tx.SetValue("a", "y", val)
+			}))
+		})
+		run(func(i int) {
+			assert.NoError(t, f.Update(func(tx *Tx) error {
+				x, _ := tx.Value("a", "x")
+				y, _ := tx.Value("a", "y")
+				assert.Equal(t, x, y)
+				return nil
+			}))
+		})
+		run(func(i int) {
+			sec := f.Section("a.b")
+			_ = sec.Key("x").String()
+			_ = sec.KeysHash()
+			_ = sec.Keys()
+			_ = f.ChildSections("a")
+			_ = f.SectionStrings()
+			_ = f.Section("raw").Body()
+		})
+		run(func(i int) {
+			name := "tmp" + strconv.Itoa(w)
+			sec := f.Section(name)
+			key := sec.Key("k")
+			key.SetValue(strconv.Itoa(i))
+			_ = key.AddShadow("s" + strconv.Itoa(i))
+			_ = key.ValueWithShadows()
+			_ = key.AddNestedValue("n")
+			_ = key.NestedValues()
+			sec.DeleteKey("k")
+			f.DeleteSection(name)
+		})
+		run(func(i int) {
+			var buf bytes.Buffer
+			_, err := f.WriteTo(&buf)
+			assert.NoError(t, err)
+			_ = f.Snapshot().Section("a").Value("x")
+		})
+	}
+	run(func(i int) {
+		assert.NoError(t, f.Append([]byte("[c]\nn = "+strconv.Itoa(i))))
+	})
+	wg.Wait()
+
+	assert.Equal(t, f.Section("a").Key("x").String(), f.Section("a").Key("y").String())
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ByteSize is a quantity of bytes, e.g. "512MiB" or "10k".
+type ByteSize uint64
+
+// Units of byte sizes with SI prefixes.
+const (
+	Byte ByteSize = 1
+	KB            = 1000 * Byte
+	MB            = 1000 * KB
+	GB            = 1000 * MB
+	TB            = 1000 * GB
+	PB            = 1000 * TB
+	EB            = 1000 * PB
+)
+
+// Units of byte sizes with IEC prefixes.
+const (
+	KiB ByteSize = 1 << (10 * (iota + 1))
+	MiB
+	GiB
+	TiB
+	PiB
+	EiB
+)
+
+// byteSizeUnits maps lowercase unit symbols to byte sizes. SI prefixes are
+// powers of 1000 and IEC prefixes are powers of 1024.
+var byteSizeUnits = map[string]ByteSize{
+	"": Byte, "b": Byte,
+	"k": KB, "kb": KB, "ki": KiB, "kib": KiB,
+	"m": MB, "mb": MB, "mi": MiB, "mib": MiB,
+	"g": GB, "gb": GB, "gi": GiB, "gib": GiB,
+	"t": TB, "tb": TB, "ti": TiB, "tib": TiB,
+	"p": PB, "pb": PB, "pi": PiB, "pib": PiB,
+	"e": EB, "eb": EB, "ei": EiB, "eib": EiB,
+}
+
+// ParseByteSize parses a byte size which is a non-negative decimal number with
+// an optional SI or IEC unit, e.g. "100", "10k", "1.5GB" or "512MiB". Units are
+// case-insensitive.
+func ParseByteSize(str string) (ByteSize, error) {
+	num, unit := splitNumber(str)
+	mult, ok := byteSizeUnits[strings.ToLower(unit)]
+	if !ok || len(num) == 0 {
+		return 0, fmt.Errorf("parsing %q: invalid byte size", str)
+	}
+
+	// Parse integers exactly, which float64 cannot do for large values.
+	if n, err := strconv.ParseUint(num, 10, 64); err == nil {
+		if n > math.MaxUint64/uint64(mult) {
+			return 0, fmt.Errorf("parsing %q: byte size out of range", str)
+		}
+		return ByteSize(n) * mult, nil
+	}
+
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("parsing %q: invalid byte size", str)
+	}
+	f *= float64(mult)
+	if f >= math.MaxUint64 {
+		return 0, fmt.Errorf("parsing %q: byte size out of range", str)
+	}
+	return ByteSize(f), nil
+}
+
+// String returns the byte size in the largest IEC or SI unit that represents
+// it exactly, preferring IEC units, e.g. "512MiB", "10kB" or "100B".
+func (b ByteSize) String() string {
+	if b == 0 {
+		return "0B"
+	}
+	for _, u := range []struct {
+		size   ByteSize
+		symbol string
+	}{
+		{EiB, "EiB"}, {PiB, "PiB"}, {TiB, "TiB"}, {GiB, "GiB"}, {MiB, "MiB"}, {KiB, "KiB"},
+		{EB, "EB"}, {PB, "PB"}, {TB, "TB"}, {GB, "GB"}, {MB, "MB"}, {KB, "kB"},
+	} {
+		if b%u.size == 0 {
+			return strconv.FormatUint(uint64(b/u.size), 10) + u.symbol
+		}
+	}
+	return strconv.FormatUint(uint64(b), 10) + "B"
+}
+
+// Percent is a percentage, e.g. 75 for "75%".
+type Percent float64
+
+// ParsePercent parses a percentage which is a decimal number with an optional
+// "%" sign, e.g. "75%", "12.5 %" or "75".
+func ParsePercent(str string) (Percent, error) {
+	s := strings.TrimSpace(str)
+	s = strings.TrimSpace(strings.TrimSuffix(s, "%"))
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing %q: invalid percentage", str)
+	}
+	return Percent(f), nil
+}
+
+// Ratio returns the percentage as a ratio, e.g. 0.75 for 75%.
+func (p Percent) Ratio() float64 {
+	return float64(p) / 100
+}
+
+// String returns the percentage with a "%" sign, e.g. "75%".
+func (p Percent) String() string {
+	return strconv.FormatFloat(float64(p), 'f', -1, 64) + "%"
+}
+
+// Rate is a number of events per a period of time, e.g. "100/s".
+type Rate struct {
+	Count float64
+	Per   time.Duration
+}
+
+// rateUnits maps lowercase unit names of rates to periods.
+var rateUnits = map[string]time.Duration{
+	"ms": time.Millisecond,
+	"s":  time.Second, "sec": time.Second, "second": time.Second,
+	"m": time.Minute, "min": time.Minute, "minute": time.Minute,
+	"h": time.Hour, "hr": time.Hour, "hour": time.Hour,
+	"d": 24 * time.Hour, "day": 24 * time.Hour,
+}
+
+// ParseRate parses a rate in the form of "count/unit", e.g. "100/s", "5/min"
+// or "1.5/hour", or "count/duration", e.g. "10/2s".
+func ParseRate(str string) (Rate, error) {
+	i := strings.Index(str, "/")
+	if i == -1 {
+		return Rate{}, fmt.Errorf("parsing %q: invalid rate", str)
+	}
+
+	count, err := strconv.ParseFloat(strings.TrimSpace(str[:i]), 64)
+	if err != nil || count < 0 {
+		return Rate{}, fmt.Errorf("parsing %q: invalid rate", str)
+	}
+
+	unit := strings.ToLower(strings.TrimSpace(str[i+1:]))
+	per, ok := rateUnits[unit]
+	if !ok {
+		// Plurals, e.g. "seconds"
+		per, ok = rateUnits[strings.TrimSuffix(unit, "s")]
+	}
+	if !ok {
+		if per, err = time.ParseDuration(unit); err != nil || per <= 0 {
+			return Rate{}, fmt.Errorf("parsing %q: invalid rate", str)
+		}
+	}
+	return Rate{Count: count, Per: per}, nil
+}
+
+// PerSecond returns the number of events per second.
+func (r Rate) PerSecond() float64 {
+	if r.Per <= 0 {
+		return 0
+	}
+	return r.Count / r.Per.Seconds()
+}
+
+// String returns the rate in the form of "count/unit", e.g. "100/s", or
+// "count/duration", e.g. "10/2s".
+func (r Rate) String() string {
+	count := strconv.FormatFloat(r.Count, 'f', -1, 64)
+	switch r.Per {
+	case time.Millisecond:
+		return count + "/ms"
+	case time.Second:
+		return count + "/s"
+	case time.Minute:
+		return count + "/m"
+	case time.Hour:
+		return count + "/h"
+	case 24 * time.Hour:
+		return count + "/d"
+	}
+	return count + "/" + r.Per.String()
+}
+
+// splitNumber splits given string into the leading decimal number and the rest
+// with surrounding spaces trimmed.
+func splitNumber(str string) (num, rest string) {
+	str = strings.TrimSpace(str)
+	i := 0
+	for i < len(str) && (str[i] == '.' || ('0' <= str[i] && str[i] <= '9')) {
+		i++
+	}
+	return str[:i], strings.TrimSpace(str[i:])
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 Unknwon
+//
+// Licensed under the Apache License, Version 2.0 (the "License"): you may
+// not use this file except in compliance with the License. You may obtain
+// a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS, WITHOUT
+// WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied. See the
+// License for the specific language governing permissions and limitations
+// under the License.
+
+package ini
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want ByteSize
+	}{
+		{in: "0", want: 0},
+		{in: "100", want: 100},
+		{in: "100B", want: 100},
+		{in: "10k", want: 10 * KB},
+		{in: "10 kB", want: 10 * KB},
+		{in: "512MiB", want: 512 * MiB},
+		{in: "512mib", want: 512 * MiB},
+		{in: "2Gi", want: 2 * GiB},
+		{in: "1.5GB", want: 1500 * MB},
+		{in: "0.5KiB", want: 512},
+		{in: "18446744073709551615", want: 1<<64 - 1},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := ParseByteSize(test.in)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+
+	for _, in := range []string{"", "MiB", "-1", "1.2.3k", "10x", "1e3", "16EiB", "20000000000000000000"} {
+		_, err := ParseByteSize(in)
+		assert.Error(t, err, in)
+	}
+
+	assert.Equal(t, "0B", ByteSize(0).String())
+	assert.Equal(t, "512MiB", (512 * MiB).String())
+	assert.Equal(t, "10kB", (10 * KB).String())
+	assert.Equal(t, "1500MB", (1500 * MB).String())
+	assert.Equal(t, "1001B", ByteSize(1001).String())
+}
+
+func TestParsePercent(t *testing.T) {
+	p, err := ParsePercent("75%")
+	require.NoError(t, err)
+	assert.Equal(t, Percent(75), p)
+	assert.Equal(t, 0.75, p.Ratio())
+	assert.Equal(t, "75%", p.String())
+
+	p, err = ParsePercent(" 12.5 % ")
+	require.NoError(t, err)
+	assert.Equal(t, Percent(12.5), p)
+
+	p, err = ParsePercent("80")
+	require.NoError(t, err)
+	assert.Equal(t, Percent(80), p)
+
+	_, err = ParsePercent("%")
+	assert.Error(t, err)
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   Rate
+		string string
+	}{
+		{in: "100/s", want: Rate{Count: 100, Per: time.Second}, string: "100/s"},
+		{in: "5 / min", want: Rate{Count: 5, Per: time.Minute}, string: "5/m"},
+		{in: "1.5/hour", want: Rate{Count: 1.5, Per: time.Hour}, string: "1.5/h"},
+		{in: "3/seconds", want: Rate{Count: 3, Per: time.Second}, string: "3/s"},
+		{in: "1/ms", want: Rate{Count: 1, Per: time.Millisecond}, string: "1/ms"},
+		{in: "10/2s", want: Rate{Count: 10, Per: 2 * time.Second}, string: "10/2s"},
+		{in: "2/day", want: Rate{Count: 2, Per: 24 * time.Hour}, string: "2/d"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			got, err := ParseRate(test.in)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+			assert.Equal(t, test.string, got.String())
+		})
+	}
+
+	r, _ := ParseRate("60/m")
+	assert.Equal(t, 1.0, r.PerSecond())
+
+	for _, in := range []string{"", "100", "x/s", "-1/s", "1/fortnight", "1/0s", "1/-1s"} {
+		_, err := ParseRate(in)
+		assert.Error(t, err, in)
+	}
+}
+
+func TestKey_Units(t *testing.T) {
+	f, err := Load([]byte(`
+cache_size = 512MiB
+max_body = 10k
+ratio = 75%
+rate = 100/s
+sizes = 1k, 2KiB, bad, 3MB
+percents = 10%, bad, 20%
+rates = 1/s, bad, 2/m
+bad = bad
+`))
+	require.NoError(t, err)
+	sec := f.Section("")
+
+	t.Run("get values", func(t *testing.T) {
+		size, err := sec.Key("cache_size").ByteSize()
+		require.NoError(t, err)
+		assert.Equal(t, 512*MiB, size)
+		assert.Equal(t, 10*KB, sec.Key("max_body").MustByteSize())
+
+		p, err := sec.Key("ratio").Percent()
+		require.NoError(t, err)
+		assert.Equal(t, Percent(75), p)
+
+		r, err := sec.Key("rate").Rate()
+		require.NoError(t, err)
+		assert.Equal(t, Rate{Count: 100, Per: time.Second}, r)
+
+		_, err = sec.Key("bad").ByteSize()
+		assert.Error(t, err)
+	})
+
+	t.Run("get values with defaults", func(t *testing.T) {
+		assert.Equal(t, 512*MiB, sec.Key("cache_size").MustByteSize(1*KiB))
+		assert.Equal(t, 4*KiB, sec.Key("missing_size").MustByteSize(4*KiB))
+		assert.Equal(t, "4KiB", sec.Key("missing_size").String())
+		assert.Equal(t, Percent(50), sec.Key("missing_ratio").MustPercent(50))
+		assert.Equal(t, "50%", sec.Key("missing_ratio").String())
+		assert.Equal(t, Rate{Count: 10, Per: time.Minute}, sec.Key("missing_rate").MustRate(Rate{Count: 10, Per: time.Minute}))
+		assert.Equal(t, "10/m", sec.Key("missing_rate").String())
+	})
+
+	t.Run("get values in range", func(t *testing.T) {
+		assert.Equal(t, 512*MiB, sec.Key("cache_size").RangeByteSize(1*MiB, 0, 1*GiB))
+		assert.Equal(t, 1*MiB, sec.Key("cache_size").RangeByteSize(1*MiB, 0, 1*MB))
+		assert.Equal(t, Percent(75), sec.Key("ratio").RangePercent(50, 0, 100))
+		assert.Equal(t, Percent(50), sec.Key("ratio").RangePercent(50, 0, 60))
+		lo, hi := Rate{Count: 1, Per: time.Second}, Rate{Count: 1000, Per: time.Second}
+		assert.Equal(t, Rate{Count: 100, Per: time.Second}, sec.Key("rate").RangeRate(lo, lo, hi))
+		assert.Equal(t, lo, sec.Key("rate").RangeRate(lo, lo, Rate{Count: 60, Per: time.Minute}))
+	})
+
+	t.Run("get slices", func(t *testing.T) {
+		assert.Equal(t, []ByteSize{KB, 2 * KiB, 0, 3 * MB}, sec.Key("sizes").ByteSizes(","))
+		assert.Equal(t, []ByteSize{KB, 2 * KiB, 3 * MB}, sec.Key("sizes").ValidByteSizes(","))
+		_, err := sec.Key("sizes").StrictByteSizes(",")
+		assert.Error(t, err)
+
+		assert.Equal(t, []Percent{10, 0, 20}, sec.Key("percents").Percents(","))
+		assert.Equal(t, []Percent{10, 20}, sec.Key("percents").ValidPercents(","))
+		_, err = sec.Key("percents").StrictPercents(",")
+		assert.Error(t, err)
+
+		assert.Equal(t, []Rate{{Count: 1, Per: time.Second}, {}, {Count: 2, Per: time.Minute}}, sec.Key("rates").Rates(","))
+		assert.Equal(t, []Rate{{Count: 1, Per: time.Second}, {Count: 2, Per: time.Minute}}, sec.Key("rates").ValidRates(","))
+		_, err = sec.Key("rates").StrictRates(",")
+		assert.Error(t, err)
+	})
+}
+
+func TestMapToAndReflectFromUnits(t *testing.T) {
+	type limits struct {
+		CacheSize ByteSize   `ini:"cache_size"`
+		MaxBody   *ByteSize  `ini:"max_body"`
+		Ratio     Percent    `ini:"ratio"`
+		Rate      Rate       `ini:"rate"`
+		Burst     *Rate      `ini:"burst"`
+		Sizes     []ByteSize `ini:"sizes"`
+		Rates     []Rate     `ini:"rates" delim:"|"`
+		Unset     Rate       `ini:"unset,omitempty"`
+	}
+
+	f, err := Load([]byte(`
+[limits]
+cache_size = 512MiB
+max_body = 10k
+ratio = 75%
+rate = 100/s
+burst = 10/2s
+sizes = 1k, 2KiB
+rates = 1/s | 2/m
+`))
+	require.NoError(t, err)
+
+	l := new(limits)
+	require.NoError(t, f.Section("limits").StrictMapTo(l))
+	assert.Equal(t, 512*MiB, l.CacheSize)
+	require.NotNil(t, l.MaxBody)
+	assert.Equal(t, 10*KB, *l.MaxBody)
+	assert.Equal(t, Percent(75), l.Ratio)
+	assert.Equal(t, Rate{Count: 100, Per: time.Second}, l.Rate)
+	require.NotNil(t, l.Burst)
+	assert.Equal(t, Rate{Count: 10, Per: 2 * time.Second}, *l.Burst)
+	assert.Equal(t, []ByteSize{KB, 2 * KiB}, l.Sizes)
+	assert.Equal(t, []Rate{{Count: 1, Per: time.Second}, {Count: 2, Per: time.Minute}}, l.Rates)
+
+	err = Empty().Section("").StrictMapTo(l)
+	assert.NoError(t, err)
+	bad, err := Load([]byte("cache_size = huge"))
+	require.NoError(t, err)
+	assert.Error(t, bad.StrictMapTo(l))
+
+	cfg := Empty()
+	require.NoError(t, cfg.Section("limits").ReflectFrom(l))
+	var buf bytes.Buffer
+	_, err = cfg.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, `[limits]
+cache_size = 512MiB
+max_body   = 10kB
+ratio      = 75%
+rate       = 100/s
+burst      = 10/2s
+sizes      = 1kB,2KiB
+rates      = 1/s|2/m
+`, buf.String())
+}